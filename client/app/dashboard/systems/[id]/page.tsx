@@ -199,6 +199,29 @@ export default async function SystemDetailsPage({
                 </p>
               </div>
             </div>
+
+            <div>
+              <div className="flex items-center justify-between mb-2">
+                <p className="text-sm font-medium">Security Logging</p>
+                <Badge
+                  variant={system.logging_ok ? "default" : "destructive"}
+                >
+                  {system.logging_ok ? "Healthy" : "Issue Detected"}
+                </Badge>
+              </div>
+              <div className="flex items-center gap-2">
+                {system.logging_ok ? (
+                  <Check className="h-5 w-5 text-green-500" />
+                ) : (
+                  <X className="h-5 w-5 text-red-500" />
+                )}
+                <p className="text-sm">
+                  {system.logging_ok
+                    ? "Audit and system logs are recorded and retained"
+                    : "Audit daemon, log retention or log disk space needs attention"}
+                </p>
+              </div>
+            </div>
           </CardContent>
         </Card>
       </div>
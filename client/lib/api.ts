@@ -12,6 +12,7 @@ export interface SystemReport {
   disk_encrypted: boolean;
   disk_encryption_method: string;
   sleep_ok: boolean;
+  logging_ok?: boolean;
   reported_at: string;
   __v: number;
 }
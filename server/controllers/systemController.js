@@ -1,6 +1,7 @@
 // controllers/systemController.js
 import System from '../models/systemModel.js';
 import Report from '../models/reportModel.js';
+import Inventory from '../models/inventoryModel.js';
 import jwt from 'jsonwebtoken';
 const JWT_SECRET = process.env.JWT_SECRET || 'your_jwt_secret';
 const TOKEN_EXPIRY = '7d';
@@ -72,6 +73,7 @@ export const getFilteredSystems = async (req, res) => {
     if (req.query.antivirus_active) filters.antivirus_active = req.query.antivirus_active === 'true';
     if (req.query.disk_encrypted) filters.disk_encrypted = req.query.disk_encrypted === 'true';
     if (req.query.os_up_to_date) filters.os_up_to_date = req.query.os_up_to_date === 'true';
+    if (req.query.logging_ok) filters.logging_ok = req.query.logging_ok === 'true';
 
     const results = await Report.find(filters);
     res.status(200).json(results);
@@ -79,3 +81,52 @@ export const getFilteredSystems = async (req, res) => {
     res.status(500).json({ error: 'Failed to fetch filtered systems' });
   }
 };
+
+// Must match Package.Key() in the agent: RPMs can have several versions
+// of one package installed, so their key includes the version
+const packageKey = (pkg) => {
+  const key = `${pkg.source}/${pkg.name}:${pkg.architecture || ''}`;
+  return pkg.source === 'rpm' ? `${key}@${pkg.version}` : key;
+};
+
+export const reportInventory = async (req, res) => {
+  try {
+    const { machine_id, full, upserted = [], removed = [] } = req.body;
+
+    let inventory = await Inventory.findOne({ machine_id });
+    if (full) {
+      inventory = inventory || new Inventory({ machine_id });
+      inventory.packages = upserted;
+    } else {
+      // A delta needs a base to apply to; ask the agent for a full upload
+      if (!inventory) {
+        return res.status(409).json({ error: 'Full inventory required' });
+      }
+      const packages = new Map(inventory.packages.map((pkg) => [packageKey(pkg), pkg]));
+      removed.forEach((key) => packages.delete(key));
+      upserted.forEach((pkg) => packages.set(packageKey(pkg), pkg));
+      inventory.packages = [...packages.values()];
+    }
+
+    await inventory.save();
+    res.status(200).json({ message: 'Inventory updated', count: inventory.packages.length });
+  } catch (err) {
+    res.status(500).json({ error: 'Failed to update inventory' });
+  }
+};
+
+export const getInventoryByMachineId = async (req, res) => {
+  const { machine_id } = req.params;
+
+  try {
+    const inventory = await Inventory.findOne({ machine_id });
+
+    if (!inventory) {
+      return res.status(404).json({ error: 'Inventory not found' });
+    }
+
+    res.status(200).json(inventory);
+  } catch (err) {
+    res.status(500).json({ error: 'Failed to fetch inventory' });
+  }
+};
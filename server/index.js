@@ -13,6 +13,11 @@ connectDB();
 
 // Middleware
 app.use(cors());
+// A full package inventory is several hundred KB and a report with
+// vulnerability findings can pass the default 100kb limit; register these
+// parsers first so the global one skips them
+app.use('/api/systems/inventory', express.json({ limit: '10mb' }));
+app.use('/api/systems/report', express.json({ limit: '1mb' }));
 app.use(express.json());
 app.use(morgan('dev'));
 
@@ -0,0 +1,26 @@
+// models/inventoryModel.js
+import mongoose from 'mongoose';
+
+const packageSchema = new mongoose.Schema({
+  name: { type: String, required: true },
+  version: { type: String },
+  architecture: { type: String },
+  source: { type: String },
+  installed_at: { type: Date },
+  source_name: { type: String },
+}, { _id: false });
+
+const inventorySchema = new mongoose.Schema({
+  machine_id: { type: String, required: true, unique: true },
+  packages: [packageSchema],
+  updated_at: { type: Date, default: Date.now }
+});
+
+inventorySchema.pre('save', function (next) {
+  this.updated_at = Date.now();
+  next();
+});
+
+const Inventory = mongoose.model('Inventory', inventorySchema);
+
+export default Inventory;
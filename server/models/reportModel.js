@@ -12,12 +12,38 @@ const reportSchema = new mongoose.Schema({
   os_up_to_date: { type: Boolean },
   current_os_version: { type: String },
   latest_os_version: { type: String },
+  os_updates: { type: mongoose.Schema.Types.Mixed },
+  eol: { type: mongoose.Schema.Types.Mixed },
+  reboot: { type: mongoose.Schema.Types.Mixed },
+  auto_updates: { type: mongoose.Schema.Types.Mixed },
 
   antivirus_exists: { type: Boolean },
   antivirus_active: { type: Boolean },
   antivirus_name: { type: String },
+  antivirus_products: { type: mongoose.Schema.Types.Mixed },
+  clamav: { type: mongoose.Schema.Types.Mixed },
 
   sleep_ok: { type: Boolean },
+  sleep_users: { type: mongoose.Schema.Types.Mixed },
+  logind: { type: mongoose.Schema.Types.Mixed },
+
+  logging_ok: { type: Boolean },
+  logging: { type: mongoose.Schema.Types.Mixed },
+
+  time_sync: { type: mongoose.Schema.Types.Mixed },
+
+  tpm: { type: mongoose.Schema.Types.Mixed },
+  firewall: { type: mongoose.Schema.Types.Mixed },
+  listening_ports: { type: mongoose.Schema.Types.Mixed },
+  mac: { type: mongoose.Schema.Types.Mixed },
+  sysctl: { type: mongoose.Schema.Types.Mixed },
+  mounts: { type: mongoose.Schema.Types.Mixed },
+  usb: { type: mongoose.Schema.Types.Mixed },
+  ssh: { type: mongoose.Schema.Types.Mixed },
+  accounts: { type: mongoose.Schema.Types.Mixed },
+  password_policy: { type: mongoose.Schema.Types.Mixed },
+  screen_lock: { type: mongoose.Schema.Types.Mixed },
+  vulnerabilities: { type: mongoose.Schema.Types.Mixed },
 
   reported_at: { type: Date, default: Date.now }
 });
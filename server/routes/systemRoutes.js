@@ -5,15 +5,19 @@ import {
   reportSystem,
   getSystems,
   getReportByMachineId,
-  getFilteredSystems
+  getFilteredSystems,
+  reportInventory,
+  getInventoryByMachineId
 } from '../controllers/systemController.js';
 
 const router = express.Router();
 
 router.post('/register', registerSystem);
 router.post('/report', reportSystem);
+router.post('/inventory', reportInventory);
 router.get('/', getSystems);
 router.get('/:machine_id', getReportByMachineId);
+router.get('/:machine_id/inventory', getInventoryByMachineId);
 router.get('/filters', getFilteredSystems);
 
 
@@ -1,10 +1,12 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"sysutility/config"
 	"sysutility/internal/checks"
+	"sysutility/internal/inventory"
 	"sysutility/internal/reporter"
 	"time"
 )
@@ -20,30 +22,94 @@ func main() {
 
 	if cfg.Report != nil {
 		fmt.Println("Sending Report")
-		reporter.SendWithAuth(*cfg.Report, cfg.AuthToken)
+		if err := reporter.SendWithAuth(*cfg.Report, cfg.AuthToken); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to send report: %v\n", err)
+		}
 	}
 
 	for {
 		fmt.Println("Performing system check...")
 
-		currentReport := checks.RunAllChecks()
+		// Collected once per cycle for both the checks and the upload
+		inv := inventory.Collect(cfg.MachineID)
+		currentReport := checks.RunAllChecks(cfg.Policy, inv.Packages)
 		currentReport.MachineID = cfg.MachineID
 		currentReport.Hostname = cfg.Hostname
 		currentReport.OS = cfg.OS
+		if err := reporter.MeasureClockSkew(); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to measure server clock skew: %v\n", err)
+		}
+		if skew, ok := reporter.ServerClockSkew(); ok {
+			checks.ApplyServerSkew(&currentReport, skew)
+			if currentReport.TimeSync.ServerSkewExceeded {
+				fmt.Printf("Warning: local clock differs from server by %s\n", skew.Round(time.Second))
+			}
+		}
 
 		if cfg.Report == nil || checks.HasChangedFrom(*cfg.Report, currentReport) {
 			fmt.Println("Change detected in system report. Sending update...")
 
-			reporter.SendWithAuth(currentReport, cfg.AuthToken)
-
-			// Save updated report to config file
-			if err := config.UpdateReport(cfg, currentReport); err != nil {
+			// Only a delivered report becomes the baseline; otherwise the
+			// change is detected and sent again next interval
+			if err := reporter.SendWithAuth(currentReport, cfg.AuthToken); err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to send report: %v\n", err)
+			} else if err := config.UpdateReport(cfg, currentReport); err != nil {
 				fmt.Fprintf(os.Stderr, "Failed to update config with new report: %v\n", err)
 			}
 		} else {
 			fmt.Println("No change in system report.")
 		}
 
+		syncInventory(cfg, inv)
+
 		time.Sleep(time.Duration(cfg.Interval) * time.Minute)
 	}
 }
+
+// maxInventoryBackoff caps how long uploads pause after the server
+// rejects one as too large.
+const maxInventoryBackoff = 24 * time.Hour
+
+var (
+	inventoryBackoff time.Duration
+	inventoryRetryAt time.Time
+)
+
+// syncInventory uploads what changed in the software inventory since the
+// last upload the server accepted.
+func syncInventory(cfg *config.Config, current inventory.Inventory) {
+	if time.Now().Before(inventoryRetryAt) {
+		return
+	}
+	previous, err := config.LoadInventory()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load previous inventory: %v\n", err)
+	}
+
+	delta := inventory.Diff(previous, current)
+	if delta.Empty() {
+		return
+	}
+
+	fmt.Println("Change detected in software inventory. Sending update...")
+	err = reporter.SendInventory(delta, cfg.AuthToken)
+	if errors.Is(err, reporter.ErrInventoryResync) {
+		err = reporter.SendInventory(inventory.Diff(nil, current), cfg.AuthToken)
+	}
+	if errors.Is(err, reporter.ErrInventoryTooLarge) {
+		// Retrying every interval would resend the same full upload forever
+		inventoryBackoff = min(max(2*inventoryBackoff, time.Duration(cfg.Interval)*time.Minute), maxInventoryBackoff)
+		inventoryRetryAt = time.Now().Add(inventoryBackoff)
+		fmt.Fprintf(os.Stderr, "Inventory too large for server, retrying in %s\n", inventoryBackoff)
+		return
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to send inventory: %v\n", err)
+		return
+	}
+	inventoryBackoff = 0
+
+	if err := config.SaveInventory(&current); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to save inventory: %v\n", err)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"runtime"
 	"sysutility/internal/checks"
+	"sysutility/internal/inventory"
 	"sysutility/utils"
 )
 
@@ -18,12 +19,14 @@ type Config struct {
 	Hostname  string               `json:"hostname"`
 	Interval  int                  `json:"interval"`
 	AuthToken string               `json:"token"`
+	Policy    checks.Policy        `json:"policy"`
 	Report    *checks.SystemReport `json:"report,omitempty"`
 }
 
 var (
 	configDir         = filepath.Join(getHomeDir(), ".sysutility")
 	configPath        = filepath.Join(configDir, "config.json")
+	inventoryPath     = filepath.Join(configDir, "inventory.json")
 	serverRegisterURL = "http://localhost:5000/api/systems/register"
 )
 
@@ -96,7 +99,7 @@ func LoadOrRegister() (*Config, error) {
 	cfg.AuthToken = token
 
 	// Generate and assign first report
-	report := checks.RunAllChecks()
+	report := checks.RunAllChecks(cfg.Policy, inventory.Collect(cfg.MachineID).Packages)
 	report.MachineID = cfg.MachineID
 	report.Hostname = cfg.Hostname
 	report.OS = cfg.OS
@@ -117,3 +120,28 @@ func UpdateReport(cfg *Config, newReport checks.SystemReport) error {
 	cfg.Report = &newReport
 	return saveConfigToDisk(cfg)
 }
+
+// LoadInventory returns the last inventory the server acknowledged, or nil
+// if none has been uploaded yet.
+func LoadInventory() (*inventory.Inventory, error) {
+	data, err := os.ReadFile(inventoryPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading inventory: %v", err)
+	}
+	var inv inventory.Inventory
+	if err := json.Unmarshal(data, &inv); err != nil {
+		return nil, fmt.Errorf("invalid inventory format: %v", err)
+	}
+	return &inv, nil
+}
+
+func SaveInventory(inv *inventory.Inventory) error {
+	data, err := json.Marshal(inv)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(inventoryPath, data, 0644)
+}
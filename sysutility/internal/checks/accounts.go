@@ -0,0 +1,62 @@
+package checks
+
+// AccountStatus summarizes local accounts and who can become root.
+type AccountStatus struct {
+	// ExtraUID0 lists accounts other than root with UID 0.
+	ExtraUID0 []string `json:"extra_uid0,omitempty"`
+	// EmptyPasswords can log in without any password.
+	EmptyPasswords []string `json:"empty_passwords,omitempty"`
+
+	// ShadowReadable is false when the agent is not root; hash details are
+	// then unknown.
+	ShadowReadable bool `json:"shadow_readable"`
+	// HashAlgorithms counts usable password hashes by algorithm.
+	HashAlgorithms map[string]int `json:"hash_algorithms,omitempty"`
+	// WeakHashes are accounts hashed with DES, MD5 or SHA-1 crypt.
+	WeakHashes []string `json:"weak_hashes,omitempty"`
+
+	// AdminGroups maps sudo/wheel/admin to their members, including users
+	// whose primary group it is.
+	AdminGroups map[string][]string `json:"admin_groups,omitempty"`
+
+	SudoersReadable bool       `json:"sudoers_readable"`
+	NoPasswdRules   []SudoRule `json:"nopasswd_rules,omitempty"`
+}
+
+// SudoRule is a sudoers line with its file.
+type SudoRule struct {
+	Source string `json:"source"`
+	Rule   string `json:"rule"`
+}
+
+// weakHashAlgorithms are crypt schemes that are fast enough to brute-force.
+var weakHashAlgorithms = map[string]bool{"des": true, "md5": true, "sha1": true}
+
+// passwordHashAlgorithm names the crypt(3) scheme of a shadow hash.
+// Locked ("!", "*") and empty hashes return "".
+func passwordHashAlgorithm(hash string) string {
+	if hash == "" || hash[0] == '!' || hash[0] == '*' {
+		return ""
+	}
+	prefixes := []struct{ prefix, name string }{
+		{"$1$", "md5"},
+		{"$2a$", "bcrypt"},
+		{"$2b$", "bcrypt"},
+		{"$2y$", "bcrypt"},
+		{"$5$", "sha256"},
+		{"$6$", "sha512"},
+		{"$7$", "scrypt"},
+		{"$y$", "yescrypt"},
+		{"$gy$", "gost-yescrypt"},
+		{"$sha1$", "sha1"},
+	}
+	for _, p := range prefixes {
+		if len(hash) > len(p.prefix) && hash[:len(p.prefix)] == p.prefix {
+			return p.name
+		}
+	}
+	if len(hash) == 13 {
+		return "des"
+	}
+	return "unknown"
+}
@@ -0,0 +1,201 @@
+//go:build linux
+// +build linux
+
+package checks
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+var adminGroupNames = []string{"sudo", "wheel", "admin"}
+
+func checkAccounts() *AccountStatus {
+	passwd, err := os.ReadFile("/etc/passwd")
+	if err != nil {
+		return nil
+	}
+	status := &AccountStatus{}
+
+	primaryGID := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(passwd))
+	for scanner.Scan() {
+		fields := strings.Split(scanner.Text(), ":")
+		if len(fields) < 7 {
+			continue
+		}
+		primaryGID[fields[0]] = fields[3]
+		if fields[2] == "0" && fields[0] != "root" {
+			status.ExtraUID0 = append(status.ExtraUID0, fields[0])
+		}
+		// A hash in passwd itself, or nothing at all, bypasses shadow
+		if fields[1] == "" {
+			status.EmptyPasswords = append(status.EmptyPasswords, fields[0])
+		}
+	}
+
+	if shadow, err := os.ReadFile("/etc/shadow"); err == nil {
+		status.ShadowReadable = true
+		status.HashAlgorithms = map[string]int{}
+		scanner := bufio.NewScanner(bytes.NewReader(shadow))
+		for scanner.Scan() {
+			fields := strings.Split(scanner.Text(), ":")
+			if len(fields) < 2 {
+				continue
+			}
+			name, hash := fields[0], fields[1]
+			if hash == "" {
+				if !containsString(status.EmptyPasswords, name) {
+					status.EmptyPasswords = append(status.EmptyPasswords, name)
+				}
+				continue
+			}
+			algorithm := passwordHashAlgorithm(hash)
+			if algorithm == "" {
+				continue
+			}
+			status.HashAlgorithms[algorithm]++
+			if weakHashAlgorithms[algorithm] {
+				status.WeakHashes = append(status.WeakHashes, name)
+			}
+		}
+	}
+
+	if group, err := os.ReadFile("/etc/group"); err == nil {
+		status.AdminGroups = parseAdminGroups(group, primaryGID)
+	}
+
+	if _, err := os.Stat("/etc/sudoers"); err == nil {
+		rules, ok := readSudoersRules("/etc/sudoers", 0)
+		status.SudoersReadable = ok
+		for _, rule := range rules {
+			if isNoPasswdRule(rule.Rule) {
+				status.NoPasswdRules = append(status.NoPasswdRules, rule)
+			}
+		}
+	}
+	return status
+}
+
+// parseAdminGroups lists the members of the admin groups, adding accounts
+// whose primary GID is the group's.
+func parseAdminGroups(data []byte, primaryGID map[string]string) map[string][]string {
+	groups := map[string][]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Split(scanner.Text(), ":")
+		if len(fields) < 4 || !containsString(adminGroupNames, fields[0]) {
+			continue
+		}
+		var members []string
+		for _, m := range strings.Split(fields[3], ",") {
+			if m = strings.TrimSpace(m); m != "" && !containsString(members, m) {
+				members = append(members, m)
+			}
+		}
+		for user, gid := range primaryGID {
+			if gid == fields[2] && !containsString(members, user) {
+				members = append(members, user)
+			}
+		}
+		sort.Strings(members)
+		groups[fields[0]] = members
+	}
+	return groups
+}
+
+// readSudoersRules returns the logical lines of a sudoers file and the
+// files it includes. ok is false if the top-level file was unreadable.
+func readSudoersRules(path string, depth int) ([]SudoRule, bool) {
+	// sudo itself stops at 128 levels; a loop should not hang the agent
+	if depth > 8 {
+		return nil, true
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+
+	var rules []SudoRule
+	for _, line := range sudoersLines(data) {
+		directive, arg, _ := strings.Cut(line, " ")
+		arg = strings.Trim(strings.TrimSpace(arg), `"`)
+		switch directive {
+		case "#include", "@include":
+			if !filepath.IsAbs(arg) {
+				arg = filepath.Join(filepath.Dir(path), arg)
+			}
+			included, _ := readSudoersRules(arg, depth+1)
+			rules = append(rules, included...)
+			continue
+		case "#includedir", "@includedir":
+			if !filepath.IsAbs(arg) {
+				arg = filepath.Join(filepath.Dir(path), arg)
+			}
+			for _, file := range sudoersIncludeDir(arg) {
+				included, _ := readSudoersRules(file, depth+1)
+				rules = append(rules, included...)
+			}
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		rules = append(rules, SudoRule{Source: path, Rule: line})
+	}
+	return rules, true
+}
+
+// sudoersIncludeDir lists the files sudo reads from an includedir: those
+// without a "." or a trailing "~" in the name, in lexical order.
+func sudoersIncludeDir(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var files []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.Contains(name, ".") || strings.HasSuffix(name, "~") {
+			continue
+		}
+		files = append(files, filepath.Join(dir, name))
+	}
+	sort.Strings(files)
+	return files
+}
+
+// sudoersLines joins backslash continuations and drops blank lines.
+// Comment lines are kept so include directives can be recognized.
+func sudoersLines(data []byte) []string {
+	var lines []string
+	var current strings.Builder
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasSuffix(line, `\`) {
+			current.WriteString(strings.TrimSuffix(line, `\`))
+			current.WriteString(" ")
+			continue
+		}
+		current.WriteString(line)
+		if joined := strings.Join(strings.Fields(current.String()), " "); joined != "" {
+			lines = append(lines, joined)
+		}
+		current.Reset()
+	}
+	return lines
+}
+
+// isNoPasswdRule recognizes user specifications with a NOPASSWD tag and
+// Defaults that turn authentication off.
+func isNoPasswdRule(rule string) bool {
+	if strings.HasPrefix(rule, "Defaults") {
+		return strings.Contains(strings.ReplaceAll(rule, " ", ""), "!authenticate")
+	}
+	return strings.Contains(rule, "NOPASSWD:")
+}
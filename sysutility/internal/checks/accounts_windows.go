@@ -0,0 +1,8 @@
+//go:build windows
+// +build windows
+
+package checks
+
+func checkAccounts() *AccountStatus {
+	return nil
+}
@@ -10,7 +10,7 @@ import (
 	"strings"
 )
 
-func checkAntivirus() (bool, bool, string) {
+func checkAntivirus() (bool, bool, string, []AntivirusProduct) {
 	// Common macOS antivirus application locations
 	avLocations := []struct {
 		path string
@@ -35,10 +35,10 @@ func checkAntivirus() (bool, bool, string) {
 			// Check if the application is running
 			cmd := exec.Command("pgrep", "-f", av.name)
 			if err := cmd.Run(); err == nil {
-				return true, true, av.name
+				return true, true, av.name, nil
 			}
 			// Application exists but not running
-			return true, false, av.name
+			return true, false, av.name, nil
 		}
 	}
 
@@ -62,7 +62,7 @@ func checkAntivirus() (bool, bool, string) {
 				if len(name) > 0 {
 					name = strings.ToUpper(name[:1]) + name[1:]
 				}
-				return true, true, name
+				return true, true, name, nil
 			}
 		}
 	}
@@ -73,9 +73,9 @@ func checkAntivirus() (bool, bool, string) {
 		// Check if XProtect definitions are recent (by checking if the directory has contents)
 		files, err := filepath.Glob(filepath.Join(xprotectPath, "Contents/Resources/*"))
 		if err == nil && len(files) > 0 {
-			return true, true, "XProtect (macOS built-in)"
+			return true, true, "XProtect (macOS built-in)", nil
 		}
 	}
 
-	return false, false, ""
+	return false, false, "", nil
 }
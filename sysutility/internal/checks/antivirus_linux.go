@@ -4,75 +4,135 @@
 package checks
 
 import (
+	"bytes"
+	"context"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
+	"time"
 )
 
-func checkAntivirus() (bool, bool, string) {
-	// Common Linux antivirus packages
-	knownAntiviruses := []string{
-		"clamav", "sophos-av", "comodo", "avast",
-		"avg", "bitdefender", "eset", "f-prot",
-		"kaspersky", "mcafee", "drweb", "avira",
+// checkAntivirus matches the product catalog against the executables of
+// running processes, systemd units and install directories. The summary
+// values describe the first product providing real-time protection, or
+// else the first one found.
+func checkAntivirus() (bool, bool, string, []AntivirusProduct) {
+	products := detectAntivirusProducts(loadAVCatalog(), runningExecutables())
+	if len(products) == 0 {
+		return false, false, "", nil
 	}
 
-	// First check if any common antivirus process is running
-	for _, av := range knownAntiviruses {
-		cmd := exec.Command("pgrep", "-f", av)
-		if err := cmd.Run(); err == nil {
-			// Process found, check if daemon/service is active
-			serviceCmd := exec.Command("systemctl", "is-active", av)
-			if serviceOut, err := serviceCmd.Output(); err == nil && strings.TrimSpace(string(serviceOut)) == "active" {
-				return true, true, av
-			} else {
-				// Process running but service status unknown, consider it active
-				return true, true, av
-			}
+	summary := products[0]
+	for _, p := range products {
+		if p.RealTimeProtection {
+			summary = p
+			break
 		}
 	}
+	// A process left over from a failed unit is not protection
+	active := summary.Running && (summary.Unit == "" || summary.UnitState == "active")
+	return true, active, summary.Name, products
+}
 
-	// Next check if any of these are installed
-	for _, av := range knownAntiviruses {
-		// Check using which command
-		whichCmd := exec.Command("which", av)
-		if whichOut, err := whichCmd.Output(); err == nil && len(whichOut) > 0 {
-			// Check if relevant service is active
-			serviceCmd := exec.Command("systemctl", "is-active", av)
-			if serviceOut, err := serviceCmd.Output(); err == nil {
-				isActive := strings.TrimSpace(string(serviceOut)) == "active"
-				return true, isActive, av
+// runningExecutables returns the executable paths of all processes. When
+// /proc/<pid>/exe is unreadable (other users' processes without root) an
+// absolute argv[0] stands in for it.
+func runningExecutables() map[string]bool {
+	executables := map[string]bool{}
+	procDirs, _ := filepath.Glob("/proc/[0-9]*")
+	for _, procDir := range procDirs {
+		exe, err := os.Readlink(filepath.Join(procDir, "exe"))
+		if err != nil {
+			cmdline, err := os.ReadFile(filepath.Join(procDir, "cmdline"))
+			if err != nil {
+				continue
+			}
+			exe, _, _ = strings.Cut(string(cmdline), "\x00")
+			if !strings.HasPrefix(exe, "/") {
+				continue
 			}
-			// Package installed but status unknown
-			return true, false, av
 		}
+		// An upgraded daemon keeps running from the deleted file
+		executables[strings.TrimSuffix(exe, " (deleted)")] = true
 	}
+	return executables
+}
 
-	// Check directory existence for common antivirus installations
-	avDirs := []string{
-		"/opt/kaspersky",
-		"/opt/sophos",
-		"/opt/comodo",
-		"/opt/drweb",
-		"/opt/bitdefender",
-		"/var/lib/clamav",
-	}
-
-	for _, dir := range avDirs {
-		if _, err := os.Stat(dir); err == nil {
-			avName := strings.TrimPrefix(strings.TrimPrefix(dir, "/opt/"), "/var/lib/")
+func detectAntivirusProducts(catalog []avCatalogEntry, running map[string]bool) []AntivirusProduct {
+	var products []AntivirusProduct
+	for _, entry := range catalog {
+		product := AntivirusProduct{Name: entry.Name}
+		found := false
 
-			// Check if service is running
-			serviceCmd := exec.Command("systemctl", "is-active", avName)
-			if serviceOut, err := serviceCmd.Output(); err == nil {
-				isActive := strings.TrimSpace(string(serviceOut)) == "active"
-				return true, isActive, avName
+		for _, exe := range entry.Executables {
+			if running[exe] {
+				product.Running = true
+				found = true
 			}
+		}
+		for _, unit := range entry.Units {
+			props := systemdUnitProperties(unit, "LoadState", "ActiveState")
+			if props["LoadState"] == "loaded" {
+				product.Unit = unit
+				product.UnitState = props["ActiveState"]
+				found = true
+				break
+			}
+		}
+		for _, dir := range entry.InstallDirs {
+			if _, err := os.Stat(dir); err == nil {
+				found = true
+			}
+		}
+		if !found {
+			continue
+		}
 
-			// Directory exists but status unknown
-			return true, false, avName
+		if len(entry.RealtimeExecutables) == 0 {
+			product.RealTimeProtection = product.Running
 		}
+		for _, exe := range entry.RealtimeExecutables {
+			if running[exe] {
+				product.RealTimeProtection = true
+			}
+		}
+		product.Version = productVersion(entry.VersionCommand)
+		if entry.HealthProbe != "" {
+			product.Health = runEDRProbe(entry.HealthProbe)
+		}
+		// The agent's own view of real-time protection beats process checks
+		if product.Health != nil {
+			product.RealTimeProtection = product.Running && product.Health.RealTimeProtection
+			if product.Version == "" {
+				product.Version = product.Health.Version
+			}
+		}
+		products = append(products, product)
 	}
+	return products
+}
 
-	return false, false, ""
+// productVersion runs the catalog's version command and returns the first
+// line of output. Some agents' CLIs hang when their daemon is wedged, so
+// the command gets a few seconds at most.
+func productVersion(command []string) string {
+	if len(command) == 0 {
+		return ""
+	}
+	if _, err := exec.LookPath(command[0]); err != nil {
+		return ""
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, command[0], command[1:]...).CombinedOutput()
+	if err != nil {
+		return ""
+	}
+	for _, line := range bytes.Split(out, []byte("\n")) {
+		if line := strings.TrimSpace(string(line)); line != "" {
+			return line
+		}
+	}
+	return ""
 }
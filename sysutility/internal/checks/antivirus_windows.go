@@ -8,21 +8,21 @@ import (
 	"strings"
 )
 
-func checkAntivirus() (bool, bool, string) {
+func checkAntivirus() (bool, bool, string, []AntivirusProduct) {
 	out, err := exec.Command("powershell", "Get-CimInstance -Namespace root/SecurityCenter2 -ClassName AntivirusProduct").Output()
 	if err != nil {
-		return false, false, "unknown"
+		return false, false, "unknown", nil
 	}
 
 	output := string(out)
 	if strings.TrimSpace(output) == "" {
-		return false, false, ""
+		return false, false, "", nil
 	}
 
 	active := strings.Contains(output, "productState") && !strings.Contains(output, "0")
 	name := parseAntivirusName(output)
 
-	return true, active, name
+	return true, active, name, nil
 }
 
 func parseAntivirusName(raw string) string {
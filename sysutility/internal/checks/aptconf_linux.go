@@ -0,0 +1,157 @@
+//go:build linux
+// +build linux
+
+package checks
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const aptConfDir = "/etc/apt"
+
+// readAptConfig loads apt.conf and apt.conf.d/* in the order apt reads
+// them, so later files override earlier ones.
+func readAptConfig(dir string) map[string][]string {
+	config := map[string][]string{}
+	files := []string{filepath.Join(dir, "apt.conf")}
+	parts, _ := filepath.Glob(filepath.Join(dir, "apt.conf.d", "*"))
+	sort.Strings(parts)
+	for _, part := range parts {
+		// apt ignores backups and package manager leftovers
+		base := filepath.Base(part)
+		if strings.ContainsAny(base, "~") || strings.Contains(base, ".dpkg-") || strings.HasSuffix(base, ".bak") {
+			continue
+		}
+		files = append(files, part)
+	}
+	for _, file := range files {
+		if data, err := os.ReadFile(file); err == nil {
+			parseAptConf(data, config)
+		}
+	}
+	return config
+}
+
+// parseAptConf merges an apt.conf file into config, keyed by the full
+// option name ("APT::Periodic::Unattended-Upgrade"). Scalars replace
+// earlier values; list entries (bare strings in a block, or "Key::" "v";)
+// are appended. #clear removes an option.
+func parseAptConf(data []byte, config map[string][]string) {
+	tokens := tokenizeAptConf(string(data))
+	var scopes []string
+	prefix := func() string {
+		if len(scopes) == 0 {
+			return ""
+		}
+		return strings.Join(scopes, "::") + "::"
+	}
+
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		switch {
+		case tok == "}":
+			if len(scopes) > 0 {
+				scopes = scopes[:len(scopes)-1]
+			}
+		case tok == ";":
+		case tok == "#clear":
+			for i+1 < len(tokens) && tokens[i+1] != ";" {
+				i++
+				key := prefix() + strings.TrimSuffix(tokens[i], "::")
+				for k := range config {
+					if k == key || strings.HasPrefix(k, key+"::") {
+						delete(config, k)
+					}
+				}
+			}
+		case strings.HasPrefix(tok, `"`):
+			// A bare value inside a block is a list entry of that block
+			key := strings.TrimSuffix(prefix(), "::")
+			config[key] = append(config[key], strings.Trim(tok, `"`))
+		default:
+			if i+1 >= len(tokens) {
+				return
+			}
+			next := tokens[i+1]
+			switch {
+			case next == "{":
+				scopes = append(scopes, strings.TrimSuffix(tok, "::"))
+				i++
+			case next == ";":
+				i++
+			default:
+				key := prefix() + tok
+				value := strings.Trim(next, `"`)
+				if strings.HasSuffix(key, "::") {
+					key = strings.TrimSuffix(key, "::")
+					config[key] = append(config[key], value)
+				} else {
+					config[key] = []string{value}
+				}
+				i++
+			}
+		}
+	}
+}
+
+// tokenizeAptConf splits apt.conf syntax into quoted strings (kept with
+// their quotes), words, "{", "}" and ";", dropping comments.
+func tokenizeAptConf(s string) []string {
+	var tokens []string
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case strings.HasPrefix(s[i:], "//"):
+			for i < len(s) && s[i] != '\n' {
+				i++
+			}
+		case strings.HasPrefix(s[i:], "/*"):
+			end := strings.Index(s[i+2:], "*/")
+			if end < 0 {
+				return tokens
+			}
+			i += end + 4
+		case c == '#' && !strings.HasPrefix(s[i:], "#clear"):
+			// #include is not followed; other # lines are comments
+			for i < len(s) && s[i] != '\n' {
+				i++
+			}
+		case c == '{' || c == '}' || c == ';':
+			tokens = append(tokens, string(c))
+			i++
+		case c == '"':
+			end := strings.IndexByte(s[i+1:], '"')
+			if end < 0 {
+				return tokens
+			}
+			tokens = append(tokens, s[i:i+end+2])
+			i += end + 2
+		default:
+			start := i
+			for i < len(s) && !strings.ContainsRune(" \t\r\n{};\"", rune(s[i])) {
+				i++
+			}
+			tokens = append(tokens, s[start:i])
+		}
+	}
+	return tokens
+}
+
+// aptConfBool interprets apt's boolean and numeric option values.
+func aptConfBool(values []string) bool {
+	if len(values) == 0 {
+		return false
+	}
+	switch strings.ToLower(values[len(values)-1]) {
+	case "1", "true", "yes", "on", "enable":
+		return true
+	}
+	// Periodic options are day intervals; anything above zero enables them
+	v := values[len(values)-1]
+	return v != "" && v != "0" && isDigit(v[0])
+}
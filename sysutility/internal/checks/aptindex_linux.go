@@ -0,0 +1,283 @@
+//go:build linux
+// +build linux
+
+package checks
+
+import (
+	"bufio"
+	"compress/gzip"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	dpkgStatusPath = "/var/lib/dpkg/status"
+	aptListsDir    = "/var/lib/apt/lists"
+	aptHelperPath  = "/usr/lib/apt/apt-helper"
+)
+
+// debPackage is one stanza of a dpkg status file or apt Packages index.
+type debPackage struct {
+	Name         string
+	Version      string
+	Architecture string
+	Status       string
+}
+
+// readDebStanzas streams a Debian control file, calling fn for each
+// stanza. Only the fields the checks need are kept.
+func readDebStanzas(r io.Reader, fn func(debPackage)) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+
+	var pkg debPackage
+	flush := func() {
+		if pkg.Name != "" {
+			fn(pkg)
+		}
+		pkg = debPackage{}
+	}
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			flush()
+			continue
+		}
+		if line[0] == ' ' || line[0] == '\t' {
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch key {
+		case "Package":
+			pkg.Name = value
+		case "Version":
+			pkg.Version = value
+		case "Architecture":
+			pkg.Architecture = value
+		case "Status":
+			pkg.Status = value
+		}
+	}
+	flush()
+	return scanner.Err()
+}
+
+// readInstalledDebs returns installed packages keyed by "name:arch".
+func readInstalledDebs(path string) (map[string]debPackage, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	installed := map[string]debPackage{}
+	err = readDebStanzas(f, func(p debPackage) {
+		if strings.HasSuffix(p.Status, " installed") {
+			installed[p.Name+":"+p.Architecture] = p
+		}
+	})
+	return installed, err
+}
+
+// Pin priorities apt gives to versions by their archive's Release flags.
+const (
+	aptPriorityDefault        = 500
+	aptPriorityButAutoUpgrade = 100
+	aptPriorityNotAutomatic   = 1
+)
+
+// aptPendingFromLists compares the installed packages against the
+// Packages indices apt has already downloaded, without touching the
+// network or the dpkg lock. Archives marked NotAutomatic (e.g. Ubuntu's
+// -backports) only offer upgrades to packages already installed from
+// them, as apt's default pin priorities do. Pins in apt preferences files
+// are not read.
+func aptPendingFromLists() ([]PendingUpdate, error) {
+	installed, err := readInstalledDebs(dpkgStatusPath)
+	if err != nil {
+		return nil, err
+	}
+
+	type candidate struct {
+		version  string
+		security bool
+	}
+	// Candidates by pin priority; the installed version counts as 100
+	candidates := map[int]map[string]candidate{}
+	installedFromDefault := map[string]bool{}
+	releasePriorities := map[string]int{}
+
+	indices, _ := filepath.Glob(filepath.Join(aptListsDir, "*_Packages*"))
+	for _, index := range indices {
+		security := strings.Contains(filepath.Base(index), "-security_") ||
+			strings.Contains(filepath.Base(index), "_debian-security_")
+
+		if strings.Contains(filepath.Base(index), ".diff") {
+			continue
+		}
+
+		priority := aptPriorityDefault
+		if release := aptReleaseFile(index); release != "" {
+			if _, ok := releasePriorities[release]; !ok {
+				releasePriorities[release] = aptReleasePriority(readFileOrNil(release))
+			}
+			priority = releasePriorities[release]
+		}
+		if priority <= aptPriorityNotAutomatic {
+			continue
+		}
+		if candidates[priority] == nil {
+			candidates[priority] = map[string]candidate{}
+		}
+
+		// A damaged index only costs us its candidates
+		_ = readAptIndex(index, func(p debPackage) {
+			key := p.Name + ":" + p.Architecture
+			inst, ok := installed[key]
+			if !ok {
+				return
+			}
+			cmp := compareDpkgVersions(p.Version, inst.Version)
+			if cmp == 0 && priority >= aptPriorityDefault {
+				installedFromDefault[key] = true
+			}
+			if cmp <= 0 {
+				return
+			}
+			c := candidates[priority][key]
+			if c.version == "" || compareDpkgVersions(p.Version, c.version) > 0 {
+				c.version = p.Version
+			}
+			c.security = c.security || security
+			candidates[priority][key] = c
+		})
+	}
+
+	// A newer version in a default archive always wins. Otherwise a
+	// ButAutomaticUpgrades archive only upgrades packages whose installed
+	// version is not also in a default archive, i.e. came from it.
+	chosen := map[string]candidate{}
+	for key, c := range candidates[aptPriorityDefault] {
+		chosen[key] = c
+	}
+	for key, c := range candidates[aptPriorityButAutoUpgrade] {
+		if _, ok := chosen[key]; !ok && !installedFromDefault[key] {
+			chosen[key] = c
+		}
+	}
+
+	keys := make([]string, 0, len(chosen))
+	for key := range chosen {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var updates []PendingUpdate
+	for _, key := range keys {
+		inst := installed[key]
+		updates = append(updates, PendingUpdate{
+			Name:             inst.Name,
+			InstalledVersion: inst.Version,
+			CandidateVersion: chosen[key].version,
+			Security:         chosen[key].security,
+		})
+	}
+	return updates, nil
+}
+
+// aptReleaseFile finds the InRelease or Release file of the suite a
+// Packages list belongs to. List names flatten the URL path with "_", e.g.
+// "archive.ubuntu.com_ubuntu_dists_jammy-backports_main_binary-amd64_Packages"
+// belongs to "archive.ubuntu.com_ubuntu_dists_jammy-backports_InRelease".
+func aptReleaseFile(index string) string {
+	dir, base := filepath.Split(index)
+	for i := strings.LastIndex(base, "_"); i > 0; i = strings.LastIndex(base[:i], "_") {
+		for _, name := range []string{"InRelease", "Release"} {
+			path := filepath.Join(dir, base[:i+1]+name)
+			if fileExists(path) {
+				return path
+			}
+		}
+	}
+	return ""
+}
+
+// aptReleasePriority maps a Release file's NotAutomatic and
+// ButAutomaticUpgrades flags to apt's default pin priority.
+func aptReleasePriority(data []byte) int {
+	fields := map[string]string{}
+	for _, line := range strings.Split(string(data), "\n") {
+		// Checksum lists are indented; only top-level fields matter
+		if key, value, ok := strings.Cut(line, ":"); ok && !strings.HasPrefix(line, " ") {
+			fields[key] = strings.ToLower(strings.TrimSpace(value))
+		}
+	}
+	switch {
+	case fields["NotAutomatic"] == "yes" && fields["ButAutomaticUpgrades"] == "yes":
+		return aptPriorityButAutoUpgrade
+	case fields["NotAutomatic"] == "yes":
+		return aptPriorityNotAutomatic
+	}
+	return aptPriorityDefault
+}
+
+// readAptIndex reads a Packages file. Plain and gzip files are read
+// directly; anything else (lz4, xz) is decompressed by apt-helper.
+func readAptIndex(path string, fn func(debPackage)) error {
+	if !strings.HasSuffix(path, "_Packages") && !strings.HasSuffix(path, ".gz") {
+		cmd := exec.Command(aptHelperPath, "cat-file", path)
+		out, err := cmd.StdoutPipe()
+		if err != nil {
+			return err
+		}
+		if err := cmd.Start(); err != nil {
+			return err
+		}
+		parseErr := readDebStanzas(out, fn)
+		if err := cmd.Wait(); err != nil {
+			return err
+		}
+		return parseErr
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		r = gz
+	}
+	return readDebStanzas(r, fn)
+}
+
+// newestModTime returns the latest modification time among files
+// matching the patterns.
+func newestModTime(patterns ...string) time.Time {
+	var newest time.Time
+	for _, pattern := range patterns {
+		matches, _ := filepath.Glob(pattern)
+		for _, path := range matches {
+			if info, err := os.Stat(path); err == nil && info.ModTime().After(newest) {
+				newest = info.ModTime()
+			}
+		}
+	}
+	return newest
+}
@@ -0,0 +1,51 @@
+package checks
+
+import "reflect"
+
+// AutoUpdateStatus describes how (and whether) updates install themselves.
+type AutoUpdateStatus struct {
+	// Mechanism is "unattended-upgrades", "dnf-automatic", "yum-cron" or
+	// "softwareupdate"; empty when none is installed.
+	Mechanism string `json:"mechanism,omitempty"`
+	Enabled   bool   `json:"enabled"`
+	// SecurityUpdatesApplied is set when security updates are installed
+	// without anyone running the package manager, not merely downloaded.
+	SecurityUpdatesApplied bool `json:"security_updates_applied"`
+	// AllUpdatesApplied is set when non-security updates install as well.
+	AllUpdatesApplied bool `json:"all_updates_applied"`
+
+	Timers  []TimerState `json:"timers,omitempty"`
+	LastRun string       `json:"last_run,omitempty"`
+}
+
+// TimerState is the systemd state of a timer driving automatic updates.
+type TimerState struct {
+	Unit        string `json:"unit"`
+	Enabled     bool   `json:"enabled"`
+	Active      bool   `json:"active"`
+	LastTrigger string `json:"last_trigger,omitempty"`
+}
+
+// autoUpdatesChanged ignores when the updates last ran, which changes with
+// every daily run.
+func autoUpdatesChanged(old, new *AutoUpdateStatus) bool {
+	if old == nil || new == nil {
+		return old != new
+	}
+	a, b := *old, *new
+	a.LastRun, b.LastRun = "", ""
+	a.Timers, b.Timers = timersWithoutTrigger(a.Timers), timersWithoutTrigger(b.Timers)
+	return !reflect.DeepEqual(a, b)
+}
+
+func timersWithoutTrigger(timers []TimerState) []TimerState {
+	if timers == nil {
+		return nil
+	}
+	out := make([]TimerState, len(timers))
+	for i, t := range timers {
+		t.LastTrigger = ""
+		out[i] = t
+	}
+	return out
+}
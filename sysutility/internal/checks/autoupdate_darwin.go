@@ -0,0 +1,39 @@
+//go:build darwin
+// +build darwin
+
+package checks
+
+import (
+	"os/exec"
+	"strings"
+)
+
+const softwareUpdatePrefs = "/Library/Preferences/com.apple.SoftwareUpdate"
+
+// checkAutoUpdates reads the Software Update preferences. Security
+// responses and system data files install when CriticalUpdateInstall is
+// on; macOS updates themselves need AutomaticallyInstallMacOSUpdates.
+func checkAutoUpdates() *AutoUpdateStatus {
+	status := &AutoUpdateStatus{Mechanism: "softwareupdate"}
+	// Unset keys default to on in current macOS releases
+	check := defaultsBool("AutomaticCheckEnabled", true)
+	critical := defaultsBool("CriticalUpdateInstall", true)
+	macOS := defaultsBool("AutomaticallyInstallMacOSUpdates", false)
+
+	status.Enabled = check
+	status.SecurityUpdatesApplied = check && critical
+	status.AllUpdatesApplied = check && critical && macOS
+
+	if out, err := exec.Command("defaults", "read", softwareUpdatePrefs, "LastSuccessfulDate").Output(); err == nil {
+		status.LastRun = strings.TrimSpace(string(out))
+	}
+	return status
+}
+
+func defaultsBool(key string, fallback bool) bool {
+	out, err := exec.Command("defaults", "read", softwareUpdatePrefs, key).Output()
+	if err != nil {
+		return fallback
+	}
+	return strings.TrimSpace(string(out)) == "1"
+}
@@ -0,0 +1,147 @@
+//go:build linux
+// +build linux
+
+package checks
+
+import (
+	"os"
+	"strings"
+	"time"
+)
+
+func checkAutoUpdates() *AutoUpdateStatus {
+	if _, err := os.Stat("/usr/bin/unattended-upgrade"); err == nil {
+		return checkUnattendedUpgrades()
+	}
+	if _, err := os.Stat("/etc/dnf/automatic.conf"); err == nil {
+		return checkDnfAutomatic("/etc/dnf/automatic.conf")
+	}
+	if _, err := os.Stat("/etc/yum/yum-cron.conf"); err == nil {
+		return checkYumCron("/etc/yum/yum-cron.conf")
+	}
+	return &AutoUpdateStatus{}
+}
+
+// checkUnattendedUpgrades evaluates APT::Periodic and the origins
+// unattended-upgrades is allowed to install from. apt-daily-upgrade.timer
+// is what runs it on systemd machines.
+func checkUnattendedUpgrades() *AutoUpdateStatus {
+	config := readAptConfig(aptConfDir)
+	status := &AutoUpdateStatus{Mechanism: "unattended-upgrades"}
+
+	periodic := true
+	if v, ok := config["APT::Periodic::Enable"]; ok {
+		periodic = aptConfBool(v)
+	}
+	status.Enabled = periodic && aptConfBool(config["APT::Periodic::Unattended-Upgrade"])
+
+	var origins []string
+	origins = append(origins, config["Unattended-Upgrade::Allowed-Origins"]...)
+	origins = append(origins, config["Unattended-Upgrade::Origins-Pattern"]...)
+	security, updates := classifyAptOrigins(origins)
+
+	timersOK := true
+	for _, unit := range []string{"apt-daily.timer", "apt-daily-upgrade.timer"} {
+		if timer := timerState(unit); timer != nil {
+			status.Timers = append(status.Timers, *timer)
+			if unit == "apt-daily-upgrade.timer" {
+				timersOK = timer.Enabled || timer.Active
+			}
+		}
+	}
+
+	status.Enabled = status.Enabled && timersOK
+	status.SecurityUpdatesApplied = status.Enabled && security
+	status.AllUpdatesApplied = status.Enabled && security && updates
+
+	// The stamp is touched after every successful run
+	for _, path := range []string{"/var/lib/apt/periodic/unattended-upgrades-stamp", "/var/log/unattended-upgrades/unattended-upgrades.log"} {
+		if info, err := os.Stat(path); err == nil {
+			status.LastRun = info.ModTime().UTC().Format(time.RFC3339)
+			break
+		}
+	}
+	return status
+}
+
+// classifyAptOrigins tells whether the allowed origins cover the security
+// pocket and the -updates pocket that carries regular fixes. Entries look
+// like "${distro_id}:${distro_codename}-security" or
+// "origin=Debian,codename=${distro_codename},label=Debian-Security". The
+// plain release pocket ("${distro_id}:${distro_codename}") never changes
+// after release on Ubuntu and does not count.
+func classifyAptOrigins(origins []string) (security, updates bool) {
+	for _, origin := range origins {
+		origin = strings.ToLower(origin)
+		switch {
+		case strings.Contains(origin, "security"):
+			security = true
+		case strings.Contains(origin, "-updates"):
+			updates = true
+		}
+	}
+	return security, updates
+}
+
+// checkDnfAutomatic reads automatic.conf. The dnf-automatic-install timer
+// applies updates regardless of apply_updates.
+func checkDnfAutomatic(path string) *AutoUpdateStatus {
+	status := &AutoUpdateStatus{Mechanism: "dnf-automatic"}
+	data, _ := os.ReadFile(path)
+	commands := parseINI(data)["commands"]
+	apply := iniBool(commands["apply_updates"])
+	upgradeType := commands["upgrade_type"]
+	if upgradeType == "" {
+		upgradeType = "default"
+	}
+
+	for _, unit := range []string{"dnf-automatic.timer", "dnf-automatic-install.timer", "dnf5-automatic.timer"} {
+		timer := timerState(unit)
+		if timer == nil {
+			continue
+		}
+		status.Timers = append(status.Timers, *timer)
+		if timer.Enabled || timer.Active {
+			status.Enabled = true
+			if unit == "dnf-automatic-install.timer" {
+				apply = true
+			}
+		}
+		if timer.LastTrigger > status.LastRun {
+			status.LastRun = timer.LastTrigger
+		}
+	}
+
+	status.SecurityUpdatesApplied = status.Enabled && apply && (upgradeType == "default" || upgradeType == "security")
+	status.AllUpdatesApplied = status.Enabled && apply && upgradeType == "default"
+	return status
+}
+
+// checkYumCron covers EL7, where yum-cron runs from cron.daily while its
+// service is enabled. It keeps no record of its last run.
+func checkYumCron(path string) *AutoUpdateStatus {
+	status := &AutoUpdateStatus{Mechanism: "yum-cron"}
+	data, _ := os.ReadFile(path)
+	commands := parseINI(data)["commands"]
+	apply := iniBool(commands["apply_updates"])
+	updateCmd := commands["update_cmd"]
+	if updateCmd == "" {
+		updateCmd = "default"
+	}
+
+	props := systemdUnitProperties("yum-cron.service", "UnitFileState")
+	status.Enabled = props["UnitFileState"] == "enabled"
+	// Every update_cmd (default, security, minimal-security, ...) includes
+	// security errata; only "default" takes everything else too
+	status.SecurityUpdatesApplied = status.Enabled && apply
+	status.AllUpdatesApplied = status.Enabled && apply && updateCmd == "default"
+	return status
+}
+
+func iniBool(value string) bool {
+	switch strings.ToLower(value) {
+	case "1", "yes", "true", "on":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,10 @@
+//go:build windows
+// +build windows
+
+package checks
+
+func checkAutoUpdates() *AutoUpdateStatus {
+	// Windows Update cannot be turned off on supported editions without
+	// group policy; policy auditing is not implemented yet
+	return nil
+}
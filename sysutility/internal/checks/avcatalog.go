@@ -0,0 +1,46 @@
+package checks
+
+import (
+	_ "embed"
+	"encoding/json"
+)
+
+// avCatalogData describes the AV/EDR products the agent recognizes. Adding
+// a product only needs a catalog entry.
+//
+//go:embed av_catalog.json
+var avCatalogData []byte
+
+// AntivirusProduct is one installed AV or EDR product.
+type AntivirusProduct struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+	// Running is set when one of the product's executables is running.
+	Running bool `json:"running"`
+	// Unit and UnitState come from the first catalog unit systemd knows.
+	Unit      string `json:"unit,omitempty"`
+	UnitState string `json:"unit_state,omitempty"`
+	// RealTimeProtection is set when on-access scanning is running: the
+	// product's dedicated real-time process, or its daemon when the
+	// product has no separate one.
+	RealTimeProtection bool `json:"real_time_protection"`
+	// Health is reported by products with a health probe.
+	Health *EDRHealth `json:"health,omitempty"`
+}
+
+type avCatalogEntry struct {
+	Name                string   `json:"name"`
+	Executables         []string `json:"executables"`
+	Units               []string `json:"units"`
+	InstallDirs         []string `json:"install_dirs"`
+	VersionCommand      []string `json:"version_command,omitempty"`
+	RealtimeExecutables []string `json:"realtime_executables,omitempty"`
+	// HealthProbe names the product-specific health check to run.
+	HealthProbe string `json:"health_probe,omitempty"`
+}
+
+func loadAVCatalog() []avCatalogEntry {
+	var catalog []avCatalogEntry
+	json.Unmarshal(avCatalogData, &catalog)
+	return catalog
+}
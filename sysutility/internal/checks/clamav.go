@@ -0,0 +1,130 @@
+package checks
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultAVSignatureMaxAgeHours is how old the newest signatures may be
+// before they count as stale. ClamAV publishes daily.cvd several times a
+// day.
+const defaultAVSignatureMaxAgeHours = 48
+
+// ClamAVStatus describes the ClamAV signature databases and daemons.
+type ClamAVStatus struct {
+	DatabaseDir string           `json:"database_dir"`
+	Databases   []ClamAVDatabase `json:"databases,omitempty"`
+	// NewestBuild is the build time of the most recent database.
+	NewestBuild       string `json:"newest_build,omitempty"`
+	SignatureAgeHours int    `json:"signature_age_hours"`
+	SignaturesFresh   bool   `json:"signatures_fresh"`
+
+	ClamdRunning     bool   `json:"clamd_running"`
+	FreshclamRunning bool   `json:"freshclam_running"`
+	LastUpdateCheck  string `json:"last_update_check,omitempty"`
+	// OnAccessScanning is set when clamonacc is running against clamd.
+	OnAccessScanning bool     `json:"on_access_scanning"`
+	OnAccessPaths    []string `json:"on_access_paths,omitempty"`
+}
+
+// ClamAVDatabase is the header of one .cvd/.cld/.cud file.
+type ClamAVDatabase struct {
+	Name       string `json:"name"`
+	Version    int    `json:"version"`
+	Signatures int    `json:"signatures"`
+	BuildTime  string `json:"build_time,omitempty"`
+}
+
+// Active tells whether ClamAV actually protects the machine: a scanner
+// daemon with current signatures.
+func (s *ClamAVStatus) Active() bool {
+	return s.ClamdRunning && s.SignaturesFresh
+}
+
+// clamAVChanged compares two statuses ignoring what every freshclam run
+// changes by itself: signature age, database versions and build times, and
+// the last update check. A switch to stale signatures still shows up as
+// SignaturesFresh.
+func clamAVChanged(old, new *ClamAVStatus) bool {
+	if old == nil || new == nil {
+		return old != new
+	}
+	a, b := *old, *new
+	a.SignatureAgeHours, b.SignatureAgeHours = 0, 0
+	a.NewestBuild, b.NewestBuild = "", ""
+	a.LastUpdateCheck, b.LastUpdateCheck = "", ""
+	a.Databases, b.Databases = databaseNames(a.Databases), databaseNames(b.Databases)
+	return !reflect.DeepEqual(a, b)
+}
+
+func databaseNames(databases []ClamAVDatabase) []ClamAVDatabase {
+	if databases == nil {
+		return nil
+	}
+	names := make([]ClamAVDatabase, len(databases))
+	for i, db := range databases {
+		names[i] = ClamAVDatabase{Name: db.Name}
+	}
+	return names
+}
+
+// readCVDHeader reads the 512-byte text header shared by .cvd, .cld and
+// .cud files:
+// "ClamAV-VDB:14 Feb 2024 07-25 +0000:27185:2052357:90:md5:dsig:builder:1707895500".
+func readCVDHeader(r io.Reader) (ClamAVDatabase, time.Time, bool) {
+	header := make([]byte, 512)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return ClamAVDatabase{}, time.Time{}, false
+	}
+	return parseCVDHeader(string(bytes.TrimRight(header, " \x00")))
+}
+
+func parseCVDHeader(header string) (ClamAVDatabase, time.Time, bool) {
+	fields := strings.Split(header, ":")
+	if len(fields) < 4 || fields[0] != "ClamAV-VDB" {
+		return ClamAVDatabase{}, time.Time{}, false
+	}
+	var db ClamAVDatabase
+	db.Version, _ = strconv.Atoi(fields[2])
+	db.Signatures, _ = strconv.Atoi(fields[3])
+
+	var built time.Time
+	if len(fields) >= 9 {
+		if secs, err := strconv.ParseInt(strings.TrimSpace(fields[8]), 10, 64); err == nil {
+			built = time.Unix(secs, 0)
+		}
+	}
+	if built.IsZero() {
+		built, _ = time.Parse("02 Jan 2006 15-04 -0700", fields[1])
+	}
+	if !built.IsZero() {
+		db.BuildTime = built.UTC().Format(time.RFC3339)
+	}
+	return db, built, true
+}
+
+// readClamConf parses clamd.conf/freshclam.conf "Key value" lines. Keys
+// that may repeat (OnAccessIncludePath) keep every value.
+func readClamConf(path string) map[string][]string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	conf := map[string][]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		key, value, _ := strings.Cut(line, " ")
+		conf[key] = append(conf[key], strings.Trim(strings.TrimSpace(value), `"`))
+	}
+	return conf
+}
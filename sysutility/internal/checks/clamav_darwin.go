@@ -0,0 +1,8 @@
+//go:build darwin
+// +build darwin
+
+package checks
+
+func checkClamAV(policy Policy) *ClamAVStatus {
+	return nil
+}
@@ -0,0 +1,83 @@
+//go:build linux
+// +build linux
+
+package checks
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+var (
+	freshclamConfPaths = []string{"/etc/clamav/freshclam.conf", "/etc/freshclam.conf"}
+	clamdConfPaths     = []string{"/etc/clamav/clamd.conf", "/etc/clamd.d/scan.conf", "/etc/clamd.conf"}
+)
+
+// checkClamAV returns nil when ClamAV is not installed.
+func checkClamAV(policy Policy) *ClamAVStatus {
+	var freshclamConf, clamdConf map[string][]string
+	for _, path := range freshclamConfPaths {
+		if freshclamConf = readClamConf(path); freshclamConf != nil {
+			break
+		}
+	}
+	for _, path := range clamdConfPaths {
+		if clamdConf = readClamConf(path); clamdConf != nil {
+			break
+		}
+	}
+
+	status := &ClamAVStatus{DatabaseDir: "/var/lib/clamav"}
+	if dirs := freshclamConf["DatabaseDirectory"]; len(dirs) > 0 {
+		status.DatabaseDir = dirs[len(dirs)-1]
+	}
+	if _, err := os.Stat(status.DatabaseDir); err != nil && freshclamConf == nil && clamdConf == nil {
+		return nil
+	}
+
+	var newest time.Time
+	files, _ := filepath.Glob(filepath.Join(status.DatabaseDir, "*.c[vlu]d"))
+	sort.Strings(files)
+	for _, file := range files {
+		f, err := os.Open(file)
+		if err != nil {
+			continue
+		}
+		db, built, ok := readCVDHeader(f)
+		f.Close()
+		if !ok {
+			continue
+		}
+		db.Name = filepath.Base(file)
+		status.Databases = append(status.Databases, db)
+		if built.After(newest) {
+			newest = built
+		}
+	}
+
+	maxAge := policy.AVSignatureMaxAgeHours
+	if maxAge <= 0 {
+		maxAge = defaultAVSignatureMaxAgeHours
+	}
+	if !newest.IsZero() {
+		status.NewestBuild = newest.UTC().Format(time.RFC3339)
+		status.SignatureAgeHours = int(time.Since(newest).Hours())
+		status.SignaturesFresh = status.SignatureAgeHours <= maxAge
+	}
+
+	// freshclam rewrites freshclam.dat on every check, even when nothing
+	// changed
+	if info, err := os.Stat(filepath.Join(status.DatabaseDir, "freshclam.dat")); err == nil {
+		status.LastUpdateCheck = info.ModTime().UTC().Format(time.RFC3339)
+	}
+
+	status.ClamdRunning = userProcessRunning(-1, "clamd")
+	status.FreshclamRunning = userProcessRunning(-1, "freshclam")
+	// clamonacc only forwards fanotify events; without clamd nothing is
+	// scanned
+	status.OnAccessScanning = status.ClamdRunning && userProcessRunning(-1, "clamonacc")
+	status.OnAccessPaths = clamdConf["OnAccessIncludePath"]
+	return status
+}
@@ -0,0 +1,157 @@
+package checks
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"strings"
+)
+
+// EDRHealth is what an EDR agent reports about itself through its CLI.
+type EDRHealth struct {
+	Version string `json:"version,omitempty"`
+	AgentID string `json:"agent_id,omitempty"`
+	// Onboarded is set when the agent is licensed and enrolled with a
+	// tenant; Connected when it is actually talking to its cloud console,
+	// and nil when the agent offers no reliable way to tell.
+	Onboarded          bool     `json:"onboarded"`
+	Connected          *bool    `json:"connected,omitempty"`
+	RealTimeProtection bool     `json:"real_time_protection"`
+	Healthy            bool     `json:"healthy"`
+	Issues             []string `json:"issues,omitempty"`
+}
+
+// parseMdatpHealth parses `mdatp health --output json`. Newer releases
+// wrap managed settings as {"value": ..., "scope": ...}. The output says
+// nothing about cloud connectivity, so Connected is left to the caller.
+func parseMdatpHealth(data []byte) (EDRHealth, bool) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return EDRHealth{}, false
+	}
+	value := func(key string) interface{} {
+		var v interface{}
+		if json.Unmarshal(raw[key], &v) != nil {
+			return nil
+		}
+		if wrapped, ok := v.(map[string]interface{}); ok {
+			if inner, ok := wrapped["value"]; ok {
+				return inner
+			}
+		}
+		return v
+	}
+	str := func(key string) string {
+		s, _ := value(key).(string)
+		return s
+	}
+	boolean := func(key string) bool {
+		b, _ := value(key).(bool)
+		return b
+	}
+
+	health := EDRHealth{
+		Version:            str("app_version"),
+		AgentID:            str("edr_machine_id"),
+		RealTimeProtection: boolean("real_time_protection_enabled"),
+		Healthy:            boolean("healthy"),
+	}
+	health.Onboarded = boolean("licensed") && health.AgentID != ""
+	if !health.Onboarded {
+		health.Issues = append(health.Issues, "not onboarded")
+	}
+	if issues, ok := value("health_issues").([]interface{}); ok {
+		for _, issue := range issues {
+			if s, ok := issue.(string); ok {
+				health.Issues = append(health.Issues, s)
+			}
+		}
+	}
+	if status := str("definitions_status"); status != "" && status != "up_to_date" {
+		health.Issues = append(health.Issues, "definitions "+status)
+	}
+	if boolean("passive_mode_enabled") {
+		health.Issues = append(health.Issues, "passive mode enabled")
+	}
+	return health, true
+}
+
+// parseFalconctl parses `falconctl -g --aid --version --rfm-state`, whose
+// options print as `aid="..."`, `version = 7.10.16303.0` and
+// `rfm-state=false`, separated by commas or newlines.
+func parseFalconctl(data []byte) (EDRHealth, bool) {
+	values := map[string]string{}
+	for _, part := range strings.FieldsFunc(string(data), func(r rune) bool { return r == ',' || r == '\n' }) {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSuffix(strings.TrimSpace(value), ".")
+		values[strings.TrimSpace(key)] = strings.Trim(value, `"`)
+	}
+	if len(values) == 0 {
+		return EDRHealth{}, false
+	}
+
+	health := EDRHealth{Version: values["version"], AgentID: values["aid"]}
+	health.Onboarded = health.AgentID != ""
+	// Reduced functionality mode: the sensor does not support the
+	// running kernel and stops preventing
+	rfm := values["rfm-state"] == "true"
+	if rfm {
+		health.Issues = append(health.Issues, "reduced functionality mode")
+	}
+	if health.AgentID == "" {
+		health.Issues = append(health.Issues, "no agent ID")
+	}
+	health.RealTimeProtection = !rfm
+	health.Healthy = len(health.Issues) == 0
+	return health, true
+}
+
+var keyValueSeparator = regexp.MustCompile(`:\s*|\s{2,}`)
+
+// parseSentinelctl combines `sentinelctl version`, `management status` and
+// `control status`. Their lines are "Key: value" or "Key    value".
+func parseSentinelctl(version, management, control []byte) (EDRHealth, bool) {
+	mgmt := parseKeyValueLines(management)
+	ctrl := parseKeyValueLines(control)
+	if len(mgmt) == 0 && len(ctrl) == 0 {
+		return EDRHealth{}, false
+	}
+
+	health := EDRHealth{Version: parseKeyValueLines(version)["Agent version"]}
+	health.AgentID = mgmt["UUID"]
+	health.Onboarded = health.AgentID != ""
+	connectivity := strings.ToLower(mgmt["Connectivity"])
+	connected := connectivity == "on" || connectivity == "connected"
+	health.Connected = &connected
+	if !connected {
+		health.Issues = append(health.Issues, "not connected to management")
+	}
+
+	state := strings.ToLower(ctrl["Agent state"])
+	protection := strings.ToLower(ctrl["Protection"])
+	health.RealTimeProtection = strings.HasPrefix(state, "enabled") && protection != "disabled"
+	if !health.RealTimeProtection {
+		health.Issues = append(health.Issues, "agent "+valueOr(state, "state unknown"))
+	}
+	health.Healthy = len(health.Issues) == 0
+	return health, true
+}
+
+// parseKeyValueLines splits each line at the first colon or run of
+// spaces. Lines without a value are skipped.
+func parseKeyValueLines(data []byte) map[string]string {
+	values := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		parts := keyValueSeparator.Split(line, 2)
+		if len(parts) == 2 && parts[1] != "" {
+			values[parts[0]] = strings.TrimSpace(parts[1])
+		}
+	}
+	return values
+}
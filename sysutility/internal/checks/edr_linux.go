@@ -0,0 +1,123 @@
+//go:build linux
+// +build linux
+
+package checks
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const tcpEstablished = "01"
+
+// runEDRProbe asks an EDR agent about its health. The catalog names the
+// probe; nil means the CLI is missing or its output was not understood.
+func runEDRProbe(probe string) *EDRHealth {
+	var health EDRHealth
+	var ok bool
+	switch probe {
+	case "mdatp":
+		// Defender connects on demand, often through a proxy, so there is
+		// no socket to look for and Connected stays unknown
+		health, ok = parseMdatpHealth(edrCommand("/usr/bin/mdatp", "health", "--output", "json"))
+	case "falcon":
+		health, ok = parseFalconctl(edrCommand("/opt/CrowdStrike/falconctl", "-g", "--aid", "--version", "--rfm-state"))
+		checkCloudConnection(&health, ok, []string{"/opt/CrowdStrike/falcond", "/opt/CrowdStrike/falcon-sensor"})
+	case "sentinelone":
+		ctl := "/opt/sentinelone/bin/sentinelctl"
+		health, ok = parseSentinelctl(
+			edrCommand(ctl, "version"),
+			edrCommand(ctl, "management", "status"),
+			edrCommand(ctl, "control", "status"),
+		)
+	}
+	if !ok {
+		return nil
+	}
+	return &health
+}
+
+// checkCloudConnection fills in Connected for Falcon, whose CLI has no
+// connectivity state but whose sensor keeps a TLS connection to the cloud
+// open while connected. Connected stays unknown when the agent cannot see
+// the sensor's sockets, e.g. when it does not run as root.
+func checkCloudConnection(health *EDRHealth, ok bool, executables []string) {
+	connected, known := processConnectedTo(executables, 443)
+	if !known {
+		return
+	}
+	health.Connected = &connected
+	if ok && !connected {
+		health.Issues = append(health.Issues, "no cloud connection")
+		health.Healthy = false
+	}
+}
+
+// edrCommand runs an agent CLI with a timeout; agents' CLIs block when
+// their daemon is wedged. Output is returned even on a non-zero exit,
+// which mdatp uses for unhealthy states.
+func edrCommand(name string, args ...string) []byte {
+	if _, err := os.Stat(name); err != nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	out, _ := exec.CommandContext(ctx, name, args...).Output()
+	return out
+}
+
+// processConnectedTo tells whether a process running one of executables
+// has an established TCP connection to the remote port. known is false
+// when the agent is not root and could not read such a process's file
+// descriptors.
+func processConnectedTo(executables []string, port int) (connected, known bool) {
+	// root sees every process, so finding none is an answer too
+	known = os.Geteuid() == 0
+	inodes := map[string]bool{}
+	procDirs, _ := filepath.Glob("/proc/[0-9]*")
+	for _, procDir := range procDirs {
+		exe, err := os.Readlink(filepath.Join(procDir, "exe"))
+		if err != nil || !containsString(executables, strings.TrimSuffix(exe, " (deleted)")) {
+			continue
+		}
+		fds, err := os.ReadDir(filepath.Join(procDir, "fd"))
+		if err != nil {
+			continue
+		}
+		known = true
+		for _, fd := range fds {
+			link, err := os.Readlink(filepath.Join(procDir, "fd", fd.Name()))
+			if err == nil && strings.HasPrefix(link, "socket:[") {
+				inodes[strings.TrimSuffix(strings.TrimPrefix(link, "socket:["), "]")] = true
+			}
+		}
+	}
+	if len(inodes) == 0 {
+		return false, known
+	}
+
+	for _, proto := range []string{"tcp", "tcp6"} {
+		data, err := os.ReadFile(filepath.Join("/proc/net", proto))
+		if err != nil {
+			continue
+		}
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		scanner.Scan() // header
+		for scanner.Scan() {
+			fields := strings.Fields(scanner.Text())
+			if len(fields) < 10 || fields[3] != tcpEstablished || !inodes[fields[9]] {
+				continue
+			}
+			if _, remotePort, err := parseProcNetAddr(fields[2]); err == nil && remotePort == port {
+				return true, true
+			}
+		}
+	}
+	return false, known
+}
@@ -0,0 +1,134 @@
+package checks
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func readEDRFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "edr", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func boolPtr(b bool) *bool { return &b }
+
+func TestParseMdatpHealth(t *testing.T) {
+	tests := []struct {
+		fixture string
+		want    EDRHealth
+	}{
+		{"mdatp_health.json", EDRHealth{
+			Version:            "101.23072.0021",
+			AgentID:            "5c2e8f7a1b9d4e3c6a0f2d8b7e1c9a4f3d6b8e2a",
+			Onboarded:          true,
+			RealTimeProtection: true,
+			Healthy:            true,
+		}},
+		{"mdatp_health_wrapped.json", EDRHealth{
+			Version:            "101.24032.0007",
+			AgentID:            "9a7d3e1f5b2c8d4a6e0f1b3c5d7e9f2a4b6c8d0e",
+			Onboarded:          true,
+			RealTimeProtection: true,
+			Healthy:            true,
+		}},
+		{"mdatp_health_unhealthy.json", EDRHealth{
+			Version: "101.24032.0007",
+			Issues: []string{
+				"not onboarded",
+				"no active supplementary event provider",
+				"definitions outdated",
+				"passive mode enabled",
+			},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			got, ok := parseMdatpHealth(readEDRFixture(t, tt.fixture))
+			if !ok {
+				t.Fatal("not parsed")
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	if _, ok := parseMdatpHealth([]byte("ATTENTION: No license found. Contact your administrator for help.\n")); ok {
+		t.Error("parsed non-JSON output")
+	}
+}
+
+func TestParseFalconctl(t *testing.T) {
+	tests := []struct {
+		fixture string
+		want    EDRHealth
+	}{
+		{"falconctl.txt", EDRHealth{
+			Version:            "7.10.16303.0",
+			AgentID:            "0a1b2c3d4e5f60718293a4b5c6d7e8f9",
+			Onboarded:          true,
+			RealTimeProtection: true,
+			Healthy:            true,
+		}},
+		{"falconctl_rfm.txt", EDRHealth{
+			Version: "7.05.17706.0",
+			Issues:  []string{"reduced functionality mode", "no agent ID"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			got, ok := parseFalconctl(readEDRFixture(t, tt.fixture))
+			if !ok {
+				t.Fatal("not parsed")
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSentinelctl(t *testing.T) {
+	tests := []struct {
+		name                string
+		management, control string
+		want                EDRHealth
+	}{
+		{"healthy", "sentinelctl_management.txt", "sentinelctl_control.txt", EDRHealth{
+			Version:            "23.4.2.14",
+			AgentID:            "4f8e2a1c9b7d46e3a5f0c2d8b1e7a9c3",
+			Onboarded:          true,
+			Connected:          boolPtr(true),
+			RealTimeProtection: true,
+			Healthy:            true,
+		}},
+		{"offline and disabled", "sentinelctl_management_offline.txt", "sentinelctl_control_disabled.txt", EDRHealth{
+			Version:   "23.4.2.14",
+			AgentID:   "4f8e2a1c9b7d46e3a5f0c2d8b1e7a9c3",
+			Onboarded: true,
+			Connected: boolPtr(false),
+			Issues:    []string{"not connected to management", "agent disabled (by management)"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseSentinelctl(
+				readEDRFixture(t, "sentinelctl_version.txt"),
+				readEDRFixture(t, tt.management),
+				readEDRFixture(t, tt.control),
+			)
+			if !ok {
+				t.Fatal("not parsed")
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
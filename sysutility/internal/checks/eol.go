@@ -0,0 +1,91 @@
+package checks
+
+import (
+	_ "embed"
+	"encoding/json"
+	"os"
+	"strings"
+	"time"
+)
+
+// eolTable is the end-of-life table shipped with the agent. Operators can
+// point Policy.EOLTablePath at a newer copy without rebuilding.
+//
+//go:embed eol.json
+var eolTable []byte
+
+// EOLStatus tells whether the distribution release still gets updates.
+// Status is "supported", "extended_support" (only through paid or LTS
+// programs such as Ubuntu ESM or Debian LTS), "eol" or "unknown".
+type EOLStatus struct {
+	Distribution    string `json:"distribution"`
+	Version         string `json:"version"`
+	Status          string `json:"status"`
+	EOLDate         string `json:"eol_date,omitempty"`
+	ExtendedEOLDate string `json:"extended_eol_date,omitempty"`
+	TableSource     string `json:"table_source"`
+}
+
+type eolEntry struct {
+	ID          string `json:"id"`
+	Version     string `json:"version"`
+	EOL         string `json:"eol"`
+	ExtendedEOL string `json:"extended_eol,omitempty"`
+}
+
+// loadEOLTable reads the table at path, falling back to the bundled one
+// when path is empty or unreadable.
+func loadEOLTable(path string) ([]eolEntry, string) {
+	if path != "" {
+		if data, err := os.ReadFile(path); err == nil {
+			var entries []eolEntry
+			if err := json.Unmarshal(data, &entries); err == nil {
+				return entries, path
+			}
+		}
+	}
+	var entries []eolEntry
+	json.Unmarshal(eolTable, &entries)
+	return entries, "bundled"
+}
+
+// evaluateEOL looks up a release by os-release ID and VERSION_ID. Entries
+// may name only the major version ("9" matches VERSION_ID "9.3").
+func evaluateEOL(entries []eolEntry, id, versionID string, now time.Time) EOLStatus {
+	status := EOLStatus{Distribution: id, Version: versionID, Status: "unknown"}
+
+	var match *eolEntry
+	for i, e := range entries {
+		if e.ID != id {
+			continue
+		}
+		if e.Version == versionID {
+			match = &entries[i]
+			break
+		}
+		if strings.HasPrefix(versionID, e.Version+".") && (match == nil || len(e.Version) > len(match.Version)) {
+			match = &entries[i]
+		}
+	}
+	if match == nil {
+		return status
+	}
+
+	status.EOLDate = match.EOL
+	status.ExtendedEOLDate = match.ExtendedEOL
+	eol, err := time.Parse("2006-01-02", match.EOL)
+	if err != nil {
+		return status
+	}
+	// Support ends at the end of the listed day
+	if now.Before(eol.AddDate(0, 0, 1)) {
+		status.Status = "supported"
+		return status
+	}
+	if extended, err := time.Parse("2006-01-02", match.ExtendedEOL); err == nil && now.Before(extended.AddDate(0, 0, 1)) {
+		status.Status = "extended_support"
+		return status
+	}
+	status.Status = "eol"
+	return status
+}
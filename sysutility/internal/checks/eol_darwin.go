@@ -0,0 +1,9 @@
+//go:build darwin
+// +build darwin
+
+package checks
+
+func checkEOL(policy Policy) *EOLStatus {
+	// The table only covers Linux distributions
+	return nil
+}
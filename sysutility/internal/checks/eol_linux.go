@@ -0,0 +1,17 @@
+//go:build linux
+// +build linux
+
+package checks
+
+import "time"
+
+func checkEOL(policy Policy) *EOLStatus {
+	release := readOSRelease()
+	if release["ID"] == "" {
+		return nil
+	}
+	entries, source := loadEOLTable(policy.EOLTablePath)
+	status := evaluateEOL(entries, release["ID"], release["VERSION_ID"], time.Now())
+	status.TableSource = source
+	return &status
+}
@@ -0,0 +1,11 @@
+package checks
+
+// FirewallStatus reports whether unsolicited inbound traffic is filtered and
+// which backend the verdict was derived from.
+type FirewallStatus struct {
+	Active             bool   `json:"active"`
+	Backend            string `json:"backend,omitempty"`
+	DefaultInputPolicy string `json:"default_input_policy,omitempty"`
+	InboundDropped     bool   `json:"inbound_dropped"`
+	Evidence           string `json:"evidence,omitempty"`
+}
@@ -0,0 +1,9 @@
+//go:build darwin
+// +build darwin
+
+package checks
+
+func checkFirewall() *FirewallStatus {
+	// Not implemented yet
+	return nil
+}
@@ -0,0 +1,249 @@
+//go:build linux
+// +build linux
+
+package checks
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os/exec"
+	"strings"
+)
+
+func checkFirewall() *FirewallStatus {
+	// firewalld and ufw are front-ends that generate nftables/iptables rules,
+	// so ask them first: their configuration is the operator's intent.
+	if out, err := exec.Command("firewall-cmd", "--state").Output(); err == nil && strings.TrimSpace(string(out)) == "running" {
+		zone := "public"
+		if zoneOut, err := exec.Command("firewall-cmd", "--get-default-zone").Output(); err == nil {
+			zone = strings.TrimSpace(string(zoneOut))
+		}
+		target := "default"
+		if targetOut, err := exec.Command("firewall-cmd", "--permanent", "--zone="+zone, "--get-target").Output(); err == nil {
+			target = strings.TrimSpace(string(targetOut))
+		}
+		policy := firewalldTargetPolicy(target)
+		return &FirewallStatus{
+			Active:             true,
+			Backend:            "firewalld",
+			DefaultInputPolicy: policy,
+			InboundDropped:     policy != "accept",
+			Evidence:           "firewalld running, default zone " + zone + " (target " + target + ")",
+		}
+	}
+
+	if out, err := exec.Command("ufw", "status", "verbose").Output(); err == nil {
+		active, incoming := parseUfwStatus(out)
+		if active {
+			return &FirewallStatus{
+				Active:             true,
+				Backend:            "ufw",
+				DefaultInputPolicy: incoming,
+				InboundDropped:     incoming == "deny" || incoming == "reject",
+				Evidence:           "ufw active, default incoming policy " + incoming,
+			}
+		}
+	}
+
+	if out, err := exec.Command("nft", "-j", "list", "ruleset").Output(); err == nil {
+		if policy, chains, err := parseNftRuleset(out); err == nil && chains > 0 {
+			return &FirewallStatus{
+				Active:             true,
+				Backend:            "nftables",
+				DefaultInputPolicy: policy,
+				InboundDropped:     policy != "accept",
+				Evidence:           "nftables input hook: " + policy,
+			}
+		}
+	}
+
+	if out, err := exec.Command("iptables-save", "-t", "filter").Output(); err == nil {
+		if policy, rules := parseIptablesSave(out); policy != "" {
+			return &FirewallStatus{
+				Active:             rules > 0 || policy != "accept",
+				Backend:            "iptables",
+				DefaultInputPolicy: policy,
+				InboundDropped:     policy != "accept",
+				Evidence:           "iptables filter INPUT chain: " + policy,
+			}
+		}
+	}
+
+	return &FirewallStatus{
+		DefaultInputPolicy: "accept",
+		Evidence:           "no firewall backend found or readable",
+	}
+}
+
+// firewalldTargetPolicy maps a zone target to an input policy. The "default"
+// target rejects everything not explicitly allowed.
+func firewalldTargetPolicy(target string) string {
+	switch strings.ToUpper(target) {
+	case "ACCEPT":
+		return "accept"
+	case "DROP":
+		return "drop"
+	default:
+		return "reject"
+	}
+}
+
+// parseUfwStatus parses `ufw status verbose`, returning whether ufw is active
+// and its default incoming policy (e.g. "deny").
+func parseUfwStatus(data []byte) (bool, string) {
+	active := false
+	incoming := ""
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(line, "Status:"):
+			active = strings.TrimSpace(strings.TrimPrefix(line, "Status:")) == "active"
+		case strings.HasPrefix(line, "Default:"):
+			// Default: deny (incoming), allow (outgoing), disabled (routed)
+			for _, part := range strings.Split(strings.TrimPrefix(line, "Default:"), ",") {
+				part = strings.TrimSpace(part)
+				if strings.HasSuffix(part, "(incoming)") {
+					incoming = strings.TrimSpace(strings.TrimSuffix(part, "(incoming)"))
+				}
+			}
+		}
+	}
+	return active, incoming
+}
+
+// parseNftRuleset computes the effective policy of the input hook from
+// `nft -j list ruleset`. Every base chain on the hook must accept a packet
+// for it to pass, so a single dropping chain makes the whole hook drop. It
+// also returns how many input base chains were found.
+func parseNftRuleset(data []byte) (string, int, error) {
+	var doc struct {
+		Nftables []map[string]json.RawMessage `json:"nftables"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return "", 0, err
+	}
+
+	type chainKey struct{ family, table, name string }
+	type chain struct {
+		Family string `json:"family"`
+		Table  string `json:"table"`
+		Name   string `json:"name"`
+		Hook   string `json:"hook"`
+		Policy string `json:"policy"`
+	}
+	type rule struct {
+		Family string                       `json:"family"`
+		Table  string                       `json:"table"`
+		Chain  string                       `json:"chain"`
+		Expr   []map[string]json.RawMessage `json:"expr"`
+	}
+
+	policies := map[chainKey]string{}
+	var order []chainKey
+	lastRule := map[chainKey][]map[string]json.RawMessage{}
+
+	for _, entry := range doc.Nftables {
+		if raw, ok := entry["chain"]; ok {
+			var c chain
+			if err := json.Unmarshal(raw, &c); err != nil || c.Hook != "input" {
+				continue
+			}
+			switch c.Family {
+			case "ip", "ip6", "inet":
+			default:
+				continue
+			}
+			key := chainKey{c.Family, c.Table, c.Name}
+			policy := c.Policy
+			if policy == "" {
+				policy = "accept"
+			}
+			policies[key] = policy
+			order = append(order, key)
+		}
+		if raw, ok := entry["rule"]; ok {
+			var r rule
+			if err := json.Unmarshal(raw, &r); err == nil {
+				lastRule[chainKey{r.Family, r.Table, r.Chain}] = r.Expr
+			}
+		}
+	}
+
+	effective := "accept"
+	for _, key := range order {
+		policy := policies[key]
+		// A trailing unconditional drop/reject overrides an accept policy
+		if policy == "accept" {
+			if verdict := nftTerminalVerdict(lastRule[key]); verdict != "" {
+				policy = verdict
+			}
+		}
+		if policy != "accept" {
+			effective = policy
+		}
+	}
+	return effective, len(order), nil
+}
+
+// nftTerminalVerdict returns "drop" or "reject" if the rule expressions
+// amount to an unconditional drop or reject.
+func nftTerminalVerdict(expr []map[string]json.RawMessage) string {
+	verdict := ""
+	for _, e := range expr {
+		for op := range e {
+			switch op {
+			case "counter", "log", "limit":
+			case "drop":
+				verdict = "drop"
+			case "reject":
+				verdict = "reject"
+			default:
+				// Any match makes the rule conditional
+				return ""
+			}
+		}
+	}
+	return verdict
+}
+
+// parseIptablesSave returns the effective INPUT policy from iptables-save
+// output of the filter table, or "" if the chain is missing, along with the
+// number of INPUT rules.
+func parseIptablesSave(data []byte) (string, int) {
+	policy := ""
+	lastRule := ""
+	rules := 0
+	inFilter := false
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(line, "*"):
+			inFilter = line == "*filter"
+		case !inFilter:
+		case strings.HasPrefix(line, ":INPUT "):
+			fields := strings.Fields(line)
+			if len(fields) > 1 {
+				policy = strings.ToLower(fields[1])
+			}
+		case strings.HasPrefix(line, "-A INPUT "):
+			lastRule = line
+			rules++
+		}
+	}
+
+	// A catch-all "-A INPUT -j DROP" at the end behaves like a drop policy
+	if policy == "accept" {
+		switch {
+		case lastRule == "-A INPUT -j DROP":
+			return "drop", rules
+		case lastRule == "-A INPUT -j REJECT" || strings.HasPrefix(lastRule, "-A INPUT -j REJECT --reject-with "):
+			return "reject", rules
+		}
+	}
+	return policy, rules
+}
@@ -0,0 +1,9 @@
+//go:build windows
+// +build windows
+
+package checks
+
+func checkFirewall() *FirewallStatus {
+	// Not implemented yet
+	return nil
+}
@@ -0,0 +1,35 @@
+package checks
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+)
+
+// parseINI parses an INI-style file into section -> key -> value. Keys
+// outside any section land in the "" section. Later assignments win.
+func parseINI(data []byte) map[string]map[string]string {
+	sections := map[string]map[string]string{"": {}}
+	current := ""
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			current = strings.TrimSpace(line[1 : len(line)-1])
+			if sections[current] == nil {
+				sections[current] = map[string]string{}
+			}
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		sections[current][strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	return sections
+}
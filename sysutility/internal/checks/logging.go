@@ -0,0 +1,74 @@
+package checks
+
+import "reflect"
+
+// logDiskFullPercent is the filesystem usage above which logs are at risk
+// of being dropped.
+const logDiskFullPercent = 90
+
+// LoggingStatus describes the security logging pipeline.
+type LoggingStatus struct {
+	AuditdRunning bool `json:"auditd_running"`
+	// AuditEnabled is the kernel audit state: 1 enabled, 2 locked.
+	AuditEnabled int `json:"audit_enabled"`
+	// AuditRules is the number of loaded rules; -1 when it could not be
+	// read (auditctl needs root).
+	AuditRules int `json:"audit_rules"`
+
+	// JournalStorage is journald's effective Storage= setting and
+	// JournalPersistent whether logs survive a reboot.
+	JournalStorage    string `json:"journal_storage,omitempty"`
+	JournalPersistent bool   `json:"journal_persistent"`
+
+	SyslogDaemon  string         `json:"syslog_daemon,omitempty"`
+	SyslogRunning bool           `json:"syslog_running"`
+	Forwarding    []LogForwarder `json:"forwarding,omitempty"`
+
+	Disk []LogDiskUsage `json:"disk,omitempty"`
+}
+
+// LogForwarder is a remote destination configured for log shipping.
+type LogForwarder struct {
+	Daemon string `json:"daemon"`
+	Target string `json:"target"`
+	Source string `json:"source"`
+}
+
+type LogDiskUsage struct {
+	Path string `json:"path"`
+	// Bytes excludes subdirectories listed as entries of their own.
+	Bytes int64 `json:"bytes"`
+	// FilesystemUsedPercent is how full the filesystem holding Path is.
+	FilesystemUsedPercent int `json:"filesystem_used_percent"`
+}
+
+// ok tells whether events are recorded, kept, and (if the policy asks
+// for it) audited and shipped off the host.
+func (s *LoggingStatus) ok(policy Policy) bool {
+	if !(s.JournalPersistent || s.SyslogRunning) {
+		return false
+	}
+	if policy.RequireAuditd && !s.AuditdRunning {
+		return false
+	}
+	if policy.RequireLogForwarding && len(s.Forwarding) == 0 {
+		return false
+	}
+	for _, d := range s.Disk {
+		if d.FilesystemUsedPercent >= logDiskFullPercent {
+			return false
+		}
+	}
+	return true
+}
+
+// loggingChanged compares two statuses ignoring disk usage, which grows
+// with every log line; crossing the full threshold shows in LoggingOK.
+func loggingChanged(old, new *LoggingStatus) bool {
+	if old == nil || new == nil {
+		return old != new
+	}
+	a, b := *old, *new
+	a.Disk, b.Disk = nil, nil
+	return !reflect.DeepEqual(a, b)
+}
@@ -0,0 +1,252 @@
+//go:build linux
+// +build linux
+
+package checks
+
+import (
+	"bufio"
+	"bytes"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"syscall"
+)
+
+const logRoot = "/var/log"
+
+// logSubdirs are broken out of the /var/log total; they often live on
+// filesystems of their own.
+var logSubdirs = []string{"/var/log/audit", "/var/log/journal"}
+
+func checkLogging(policy Policy) (bool, *LoggingStatus) {
+	status := &LoggingStatus{AuditRules: -1}
+
+	status.AuditdRunning = userProcessRunning(-1, "auditd")
+	if out, err := exec.Command("auditctl", "-s").Output(); err == nil {
+		// "enabled 1" among other "key value" lines
+		for _, line := range strings.Split(string(out), "\n") {
+			if fields := strings.Fields(line); len(fields) == 2 && fields[0] == "enabled" {
+				status.AuditEnabled = atoiOr(fields[1], 0)
+			}
+		}
+	}
+	if out, err := exec.Command("auditctl", "-l").Output(); err == nil {
+		status.AuditRules = countAuditRules(out)
+	}
+
+	status.JournalStorage = journaldStorage()
+	switch status.JournalStorage {
+	case "persistent":
+		status.JournalPersistent = true
+	case "auto":
+		// auto only persists when /var/log/journal exists
+		_, err := os.Stat("/var/log/journal")
+		status.JournalPersistent = err == nil
+	}
+
+	switch {
+	case fileExists("/etc/rsyslog.conf"):
+		status.SyslogDaemon = "rsyslog"
+		status.SyslogRunning = userProcessRunning(-1, "rsyslogd")
+		files := append([]string{"/etc/rsyslog.conf"}, sortedGlob("/etc/rsyslog.d/*.conf")...)
+		status.Forwarding = append(status.Forwarding, findForwarders("rsyslog", files, parseRsyslogForwarding)...)
+	case fileExists("/etc/syslog-ng/syslog-ng.conf"):
+		status.SyslogDaemon = "syslog-ng"
+		status.SyslogRunning = userProcessRunning(-1, "syslog-ng")
+		files := append([]string{"/etc/syslog-ng/syslog-ng.conf"}, sortedGlob("/etc/syslog-ng/conf.d/*.conf")...)
+		status.Forwarding = append(status.Forwarding, findForwarders("syslog-ng", files, parseSyslogNGForwarding)...)
+	}
+	if upload := parseINI(readFileOrNil("/etc/systemd/journal-upload.conf"))["Upload"]["URL"]; upload != "" {
+		if props := systemdUnitProperties("systemd-journal-upload.service", "ActiveState"); props["ActiveState"] == "active" {
+			status.Forwarding = append(status.Forwarding, LogForwarder{Daemon: "journal-upload", Target: upload, Source: "/etc/systemd/journal-upload.conf"})
+		}
+	}
+
+	status.Disk = logDiskUsage(logRoot, logSubdirs)
+	return status.ok(policy), status
+}
+
+// countAuditRules counts the rules printed by `auditctl -l`.
+func countAuditRules(data []byte) int {
+	count := 0
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && line != "No rules" {
+			count++
+		}
+	}
+	return count
+}
+
+// journaldStorage resolves Storage= from journald.conf and its drop-ins.
+func journaldStorage() string {
+	storage := "auto"
+	var sources []string
+	for _, dir := range logindConfigDirs {
+		if path := filepath.Join(dir, "journald.conf"); fileExists(path) {
+			sources = append(sources, path)
+			break
+		}
+	}
+	sources = append(sources, configDropIns(logindConfigDirs, "journald.conf.d/*.conf")...)
+	for _, path := range sources {
+		if v := parseINI(readFileOrNil(path))["Journal"]["Storage"]; v != "" {
+			storage = strings.ToLower(v)
+		}
+	}
+	return storage
+}
+
+func findForwarders(daemon string, files []string, parse func([]byte) []string) []LogForwarder {
+	var forwarders []LogForwarder
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		for _, target := range parse(data) {
+			forwarders = append(forwarders, LogForwarder{Daemon: daemon, Target: target, Source: file})
+		}
+	}
+	return forwarders
+}
+
+var (
+	rsyslogLegacyForward = regexp.MustCompile(`^[^\s$]+\s+@@?(?:\([^)]*\))?([^\s;]+)`)
+	rsyslogAction        = regexp.MustCompile(`(?s)action\s*\((.*?)\)`)
+	rsyslogParam         = regexp.MustCompile(`(\w+)\s*=\s*"([^"]*)"`)
+)
+
+// parseRsyslogForwarding finds "*.* @host" / "@@host:port" selector lines
+// and omfwd/omrelp actions.
+func parseRsyslogForwarding(data []byte) []string {
+	var targets []string
+	var uncommented strings.Builder
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		uncommented.WriteString(line + "\n")
+		if m := rsyslogLegacyForward.FindStringSubmatch(line); m != nil {
+			targets = append(targets, m[1])
+		}
+	}
+
+	for _, action := range rsyslogAction.FindAllStringSubmatch(uncommented.String(), -1) {
+		params := map[string]string{}
+		for _, p := range rsyslogParam.FindAllStringSubmatch(action[1], -1) {
+			params[strings.ToLower(p[1])] = p[2]
+		}
+		if params["type"] != "omfwd" && params["type"] != "omrelp" {
+			continue
+		}
+		target := params["target"]
+		if port := params["port"]; port != "" {
+			target += ":" + port
+		}
+		if target != "" {
+			targets = append(targets, target)
+		}
+	}
+	return targets
+}
+
+var syslogNGDestination = regexp.MustCompile(`\b(network|syslog|tcp6?|udp6?)\s*\(\s*"?([^"\s);]+)`)
+
+// parseSyslogNGForwarding finds network(), syslog(), tcp() and udp()
+// drivers inside destination blocks.
+func parseSyslogNGForwarding(data []byte) []string {
+	var targets []string
+	var uncommented strings.Builder
+	for _, line := range strings.Split(string(data), "\n") {
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		uncommented.WriteString(line + "\n")
+	}
+	text := uncommented.String()
+	for {
+		start := strings.Index(text, "destination")
+		if start < 0 {
+			break
+		}
+		text = text[start+len("destination"):]
+		open := strings.IndexByte(text, '{')
+		end := strings.Index(text, "};")
+		if open < 0 || end < open {
+			continue
+		}
+		for _, m := range syslogNGDestination.FindAllStringSubmatch(text[open:end], -1) {
+			targets = append(targets, m[2])
+		}
+		text = text[end:]
+	}
+	return targets
+}
+
+// logDiskUsage walks root once and adds up its files, splitting out those
+// under subdirs so the entries are disjoint and sum to the total. Each
+// entry also reports how full its filesystem is.
+func logDiskUsage(root string, subdirs []string) []LogDiskUsage {
+	var usages []LogDiskUsage
+	index := map[string]int{}
+	for _, dir := range append([]string{root}, subdirs...) {
+		var st syscall.Statfs_t
+		if err := syscall.Statfs(dir, &st); err != nil {
+			continue
+		}
+		usage := LogDiskUsage{Path: dir}
+		if st.Blocks > 0 {
+			usage.FilesystemUsedPercent = int(100 - st.Bavail*100/st.Blocks)
+		}
+		index[dir] = len(usages)
+		usages = append(usages, usage)
+	}
+	rootIdx, ok := index[root]
+	if !ok {
+		return nil
+	}
+
+	filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil || !info.Mode().IsRegular() {
+			return nil
+		}
+		// Allocated blocks, so sparse journal files are not overcounted
+		size := info.Size()
+		if sys, ok := info.Sys().(*syscall.Stat_t); ok {
+			size = sys.Blocks * 512
+		}
+		idx := rootIdx
+		for _, dir := range subdirs {
+			if i, ok := index[dir]; ok && strings.HasPrefix(path, dir+"/") {
+				idx = i
+				break
+			}
+		}
+		usages[idx].Bytes += size
+		return nil
+	})
+	return usages
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func sortedGlob(pattern string) []string {
+	matches, _ := filepath.Glob(pattern)
+	sort.Strings(matches)
+	return matches
+}
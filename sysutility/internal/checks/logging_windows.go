@@ -0,0 +1,18 @@
+//go:build windows
+// +build windows
+
+package checks
+
+import (
+	"os/exec"
+	"strings"
+)
+
+func checkLogging(policy Policy) (bool, *LoggingStatus) {
+	// Everything goes through the Event Log service
+	out, err := exec.Command("sc", "query", "eventlog").Output()
+	if err != nil {
+		return false, nil
+	}
+	return strings.Contains(string(out), "RUNNING"), nil
+}
@@ -0,0 +1,16 @@
+package checks
+
+// LogindStatus is the effective systemd-logind power policy.
+type LogindStatus struct {
+	IdleAction                   string `json:"idle_action"`
+	IdleActionSeconds            int    `json:"idle_action_seconds"`
+	HandleLidSwitch              string `json:"handle_lid_switch"`
+	HandleLidSwitchExternalPower string `json:"handle_lid_switch_external_power"`
+	HandleLidSwitchDocked        string `json:"handle_lid_switch_docked"`
+	HandlePowerKey               string `json:"handle_power_key"`
+	HandleSuspendKey             string `json:"handle_suspend_key"`
+	HandleHibernateKey           string `json:"handle_hibernate_key"`
+
+	// Sources lists the files that were applied, in order.
+	Sources []string `json:"sources,omitempty"`
+}
@@ -0,0 +1,9 @@
+//go:build darwin
+// +build darwin
+
+package checks
+
+func checkLogind() *LogindStatus {
+	// systemd-logind only exists on Linux
+	return nil
+}
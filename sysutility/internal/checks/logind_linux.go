@@ -0,0 +1,84 @@
+//go:build linux
+// +build linux
+
+package checks
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Search paths in order of precedence, as used by systemd for its own
+// configuration files.
+var logindConfigDirs = []string{
+	"/etc/systemd",
+	"/run/systemd",
+	"/usr/local/lib/systemd",
+	"/usr/lib/systemd",
+}
+
+func checkLogind() *LogindStatus {
+	var sources []string
+
+	// Only the highest-priority main file is read
+	for _, dir := range logindConfigDirs {
+		path := filepath.Join(dir, "logind.conf")
+		if _, err := os.Stat(path); err == nil {
+			sources = append(sources, path)
+			break
+		}
+	}
+	sources = append(sources, configDropIns(logindConfigDirs, "logind.conf.d/*.conf")...)
+
+	settings := map[string]string{}
+	for _, path := range sources {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		for key, value := range parseINI(data)["Login"] {
+			settings[key] = value
+		}
+	}
+
+	return buildLogindStatus(settings, sources)
+}
+
+// buildLogindStatus applies logind's built-in defaults to the parsed
+// [Login] settings.
+func buildLogindStatus(settings map[string]string, sources []string) *LogindStatus {
+	get := func(key, fallback string) string {
+		if v := strings.TrimSpace(settings[key]); v != "" {
+			return v
+		}
+		return fallback
+	}
+
+	status := &LogindStatus{
+		IdleAction:         get("IdleAction", "ignore"),
+		HandleLidSwitch:    get("HandleLidSwitch", "suspend"),
+		HandlePowerKey:     get("HandlePowerKey", "poweroff"),
+		HandleSuspendKey:   get("HandleSuspendKey", "suspend"),
+		HandleHibernateKey: get("HandleHibernateKey", "hibernate"),
+		Sources:            sources,
+	}
+	// Unset, the external power variant follows HandleLidSwitch
+	status.HandleLidSwitchExternalPower = get("HandleLidSwitchExternalPower", status.HandleLidSwitch)
+	status.HandleLidSwitchDocked = get("HandleLidSwitchDocked", "ignore")
+
+	if d, err := parseTimespan(get("IdleActionSec", "30min")); err == nil && d > 0 {
+		status.IdleActionSeconds = int(d.Seconds())
+	}
+	return status
+}
+
+// sleeps reports whether the idle action puts the machine to sleep or
+// powers it off.
+func (s *LogindStatus) sleeps() bool {
+	switch s.IdleAction {
+	case "suspend", "hibernate", "hybrid-sleep", "suspend-then-hibernate", "poweroff":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,28 @@
+package checks
+
+// MACStatus reports the mandatory access control framework in use.
+type MACStatus struct {
+	// Framework is "selinux", "apparmor" or empty when neither is active.
+	Framework string `json:"framework,omitempty"`
+	// Mode is "enforcing", "permissive" or "disabled" for SELinux and
+	// "enabled" or "disabled" for AppArmor.
+	Mode string `json:"mode"`
+	// ConfiguredMode is the SELinux mode set for the next boot.
+	ConfiguredMode string `json:"configured_mode,omitempty"`
+	Policy         string `json:"policy,omitempty"`
+
+	// AppArmor profile counts by mode.
+	EnforceProfiles  int `json:"enforce_profiles,omitempty"`
+	ComplainProfiles int `json:"complain_profiles,omitempty"`
+
+	// UnconfinedListeners are processes reachable from the network that
+	// run outside any MAC policy.
+	UnconfinedListeners []UnconfinedListener `json:"unconfined_listeners,omitempty"`
+}
+
+type UnconfinedListener struct {
+	Executable string   `json:"executable"`
+	Unit       string   `json:"unit,omitempty"`
+	Context    string   `json:"context"`
+	Ports      []string `json:"ports"`
+}
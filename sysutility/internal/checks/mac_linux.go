@@ -0,0 +1,131 @@
+//go:build linux
+// +build linux
+
+package checks
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// checkMAC reports SELinux or AppArmor state and which of the given
+// network listeners run unconfined.
+func checkMAC(listeners []ListeningSocket) *MACStatus {
+	status := &MACStatus{Mode: "disabled"}
+
+	// /etc/selinux/config uses the same KEY=value syntax as os-release
+	selinuxConfig := parseOSRelease(readFileOrNil("/etc/selinux/config"))
+	if enforce, err := os.ReadFile("/sys/fs/selinux/enforce"); err == nil {
+		status.Framework = "selinux"
+		status.Mode = "permissive"
+		if strings.TrimSpace(string(enforce)) == "1" {
+			status.Mode = "enforcing"
+		}
+	} else if aaEnabled, err := os.ReadFile("/sys/module/apparmor/parameters/enabled"); err == nil && strings.TrimSpace(string(aaEnabled)) == "Y" {
+		status.Framework = "apparmor"
+		status.Mode = "enabled"
+		if profiles, err := os.ReadFile("/sys/kernel/security/apparmor/profiles"); err == nil {
+			counts := countAppArmorProfiles(profiles)
+			status.EnforceProfiles = counts["enforce"]
+			status.ComplainProfiles = counts["complain"]
+		}
+	} else if len(selinuxConfig) > 0 {
+		// Installed but disabled at boot: no selinuxfs
+		status.Framework = "selinux"
+	}
+	if status.Framework == "selinux" {
+		status.ConfiguredMode = strings.ToLower(selinuxConfig["SELINUX"])
+		status.Policy = selinuxConfig["SELINUXTYPE"]
+	}
+
+	if status.Mode != "disabled" {
+		status.UnconfinedListeners = findUnconfinedListeners(listeners)
+	}
+	return status
+}
+
+func readFileOrNil(path string) []byte {
+	data, _ := os.ReadFile(path)
+	return data
+}
+
+// countAppArmorProfiles counts "name (mode)" lines by mode.
+func countAppArmorProfiles(data []byte) map[string]int {
+	counts := map[string]int{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		open := strings.LastIndex(line, " (")
+		if open < 0 || !strings.HasSuffix(line, ")") {
+			continue
+		}
+		counts[line[open+2:len(line)-1]]++
+	}
+	return counts
+}
+
+// findUnconfinedListeners reads the security context of each process
+// owning a non-loopback socket.
+func findUnconfinedListeners(listeners []ListeningSocket) []UnconfinedListener {
+	groups := map[string]*UnconfinedListener{}
+	for _, l := range listeners {
+		if !l.Exposed || l.PID == 0 {
+			continue
+		}
+		context := processSecurityContext(l.PID)
+		if context == "" || !isUnconfinedContext(context) {
+			continue
+		}
+		key := l.Executable + "|" + l.Unit
+		group, ok := groups[key]
+		if !ok {
+			group = &UnconfinedListener{Executable: l.Executable, Unit: l.Unit, Context: context}
+			groups[key] = group
+		}
+		port := fmt.Sprintf("%s/%d", l.Protocol, l.Port)
+		if !containsString(group.Ports, port) {
+			group.Ports = append(group.Ports, port)
+		}
+	}
+
+	var unconfined []UnconfinedListener
+	for _, group := range groups {
+		unconfined = append(unconfined, *group)
+	}
+	sort.Slice(unconfined, func(i, j int) bool {
+		if unconfined[i].Executable != unconfined[j].Executable {
+			return unconfined[i].Executable < unconfined[j].Executable
+		}
+		return unconfined[i].Unit < unconfined[j].Unit
+	})
+	return unconfined
+}
+
+// processSecurityContext returns the label of a process. Kernels with
+// LSM stacking expose AppArmor's view under attr/apparmor.
+func processSecurityContext(pid int) string {
+	procDir := filepath.Join("/proc", fmt.Sprint(pid), "attr")
+	for _, name := range []string{"apparmor/current", "current"} {
+		if data, err := os.ReadFile(filepath.Join(procDir, name)); err == nil {
+			if context := strings.TrimSpace(strings.TrimRight(string(data), "\x00")); context != "" {
+				return context
+			}
+		}
+	}
+	return ""
+}
+
+// isUnconfinedContext recognizes AppArmor's "unconfined" and SELinux
+// unconfined domains such as system_u:system_r:unconfined_service_t:s0.
+func isUnconfinedContext(context string) bool {
+	if context == "unconfined" {
+		return true
+	}
+	parts := strings.Split(context, ":")
+	return len(parts) >= 3 && strings.HasPrefix(parts[2], "unconfined")
+}
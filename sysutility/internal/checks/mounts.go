@@ -0,0 +1,51 @@
+package checks
+
+// mountBaseline lists the mount options the CIS benchmark requires on
+// world-writable and user-owned filesystems. noexec is not required on /home
+// since it breaks users' own scripts and toolchains.
+var mountBaseline = []struct {
+	path    string
+	options []string
+}{
+	{"/tmp", []string{"nodev", "nosuid", "noexec"}},
+	{"/var/tmp", []string{"nodev", "nosuid", "noexec"}},
+	{"/dev/shm", []string{"nodev", "nosuid", "noexec"}},
+	{"/home", []string{"nodev", "nosuid"}},
+}
+
+// removableMountOptions are required on every removable media mount.
+var removableMountOptions = []string{"nodev", "nosuid", "noexec"}
+
+// MountStatus reports the hardening options on sensitive mount points and
+// on mounted removable media.
+type MountStatus struct {
+	Compliant bool             `json:"compliant"`
+	Mounts    []MountHardening `json:"mounts"`
+	// RemovableMedia lists mounted USB sticks, SD cards and optical media.
+	RemovableMedia []MountHardening `json:"removable_media,omitempty"`
+	// RemovableRestricted is true when every removable mount carries
+	// nodev, nosuid and noexec, including when none is mounted.
+	RemovableRestricted bool `json:"removable_restricted"`
+}
+
+type MountHardening struct {
+	Path string `json:"path"`
+	// Separate is false when the path is not its own mount point and
+	// inherits its options from the filesystem below it.
+	Separate bool     `json:"separate"`
+	Device   string   `json:"device,omitempty"`
+	FSType   string   `json:"fs_type,omitempty"`
+	Options  []string `json:"options,omitempty"`
+	Missing  []string `json:"missing,omitempty"`
+}
+
+// missingMountOptions returns the required options not present in options.
+func missingMountOptions(options, required []string) []string {
+	var missing []string
+	for _, opt := range required {
+		if !containsString(options, opt) {
+			missing = append(missing, opt)
+		}
+	}
+	return missing
+}
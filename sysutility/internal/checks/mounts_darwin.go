@@ -0,0 +1,8 @@
+//go:build darwin
+// +build darwin
+
+package checks
+
+func checkMounts() *MountStatus {
+	return nil
+}
@@ -0,0 +1,167 @@
+//go:build linux
+// +build linux
+
+package checks
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+func checkMounts() *MountStatus {
+	data, err := os.ReadFile("/proc/self/mountinfo")
+	if err != nil {
+		return nil
+	}
+	entries := parseMountInfo(data)
+	status := &MountStatus{Compliant: true, RemovableRestricted: true}
+
+	for _, want := range mountBaseline {
+		entry, ok := mountFor(entries, want.path)
+		if !ok {
+			continue
+		}
+		m := MountHardening{
+			Path:     want.path,
+			Separate: entry.mountPoint == want.path,
+			Device:   entry.source,
+			FSType:   entry.fsType,
+			Options:  entry.options,
+			Missing:  missingMountOptions(entry.options, want.options),
+		}
+		if len(m.Missing) > 0 {
+			status.Compliant = false
+		}
+		status.Mounts = append(status.Mounts, m)
+	}
+
+	seen := map[string]bool{}
+	for _, entry := range entries {
+		if seen[entry.mountPoint] || !isRemovableMount(entry) {
+			continue
+		}
+		seen[entry.mountPoint] = true
+		m := MountHardening{
+			Path:     entry.mountPoint,
+			Separate: true,
+			Device:   entry.source,
+			FSType:   entry.fsType,
+			Options:  entry.options,
+			Missing:  missingMountOptions(entry.options, removableMountOptions),
+		}
+		if len(m.Missing) > 0 {
+			status.RemovableRestricted = false
+		}
+		status.RemovableMedia = append(status.RemovableMedia, m)
+	}
+	return status
+}
+
+type mountInfoEntry struct {
+	majorMinor string
+	mountPoint string
+	options    []string
+	fsType     string
+	source     string
+}
+
+// parseMountInfo reads /proc/<pid>/mountinfo lines of the form
+//
+//	36 35 98:0 /mnt1 /mnt2 rw,noatime master:1 - ext3 /dev/root rw,errors=continue
+//
+// The per-mount options before the "-" separator are the ones that carry
+// nodev, nosuid and noexec.
+func parseMountInfo(data []byte) []mountInfoEntry {
+	var entries []mountInfoEntry
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		sep := -1
+		for i := 6; i < len(fields); i++ {
+			if fields[i] == "-" {
+				sep = i
+				break
+			}
+		}
+		if sep < 0 || sep+2 >= len(fields) {
+			continue
+		}
+		entries = append(entries, mountInfoEntry{
+			majorMinor: fields[2],
+			mountPoint: unescapeMountField(fields[4]),
+			options:    strings.Split(fields[5], ","),
+			fsType:     fields[sep+1],
+			source:     unescapeMountField(fields[sep+2]),
+		})
+	}
+	return entries
+}
+
+// unescapeMountField decodes the octal escapes the kernel uses for space,
+// tab, newline and backslash in paths, e.g. "My\040Drive".
+func unescapeMountField(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+4 <= len(s) {
+			if c, err := strconv.ParseUint(s[i+1:i+4], 8, 8); err == nil {
+				b.WriteByte(byte(c))
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// mountFor returns the mount that path lives on: the longest mount point
+// that is a prefix of it, preferring later entries that mount over earlier
+// ones.
+func mountFor(entries []mountInfoEntry, path string) (mountInfoEntry, bool) {
+	var best mountInfoEntry
+	found := false
+	for _, entry := range entries {
+		mp := entry.mountPoint
+		if mp != "/" && path != mp && !strings.HasPrefix(path, mp+"/") {
+			continue
+		}
+		if !found || len(mp) >= len(best.mountPoint) {
+			best, found = entry, true
+		}
+	}
+	return best, found
+}
+
+// isRemovableMount reports whether a mount is backed by removable media:
+// optical filesystems, block devices the kernel flags as removable, or
+// anything attached over USB.
+func isRemovableMount(entry mountInfoEntry) bool {
+	if entry.fsType == "iso9660" || entry.fsType == "udf" {
+		return true
+	}
+	if strings.HasPrefix(entry.majorMinor, "0:") {
+		return false
+	}
+	devPath := filepath.Join("/sys/dev/block", entry.majorMinor)
+	target, err := filepath.EvalSymlinks(devPath)
+	if err != nil {
+		return false
+	}
+	if strings.Contains(target, "/usb") {
+		return true
+	}
+	// Partitions carry no removable flag of their own; the disk above does
+	for _, dir := range []string{target, filepath.Dir(target)} {
+		if data, err := os.ReadFile(filepath.Join(dir, "removable")); err == nil {
+			return strings.TrimSpace(string(data)) == "1"
+		}
+	}
+	return false
+}
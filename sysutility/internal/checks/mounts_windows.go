@@ -0,0 +1,8 @@
+//go:build windows
+// +build windows
+
+package checks
+
+func checkMounts() *MountStatus {
+	return nil
+}
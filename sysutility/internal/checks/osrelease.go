@@ -0,0 +1,40 @@
+package checks
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"strings"
+)
+
+// readOSRelease loads /etc/os-release, falling back to the vendor copy in
+// /usr/lib as the os-release spec requires.
+func readOSRelease() map[string]string {
+	for _, path := range []string{"/etc/os-release", "/usr/lib/os-release"} {
+		if data, err := os.ReadFile(path); err == nil {
+			return parseOSRelease(data)
+		}
+	}
+	return nil
+}
+
+// parseOSRelease parses KEY=value lines, removing shell quoting.
+func parseOSRelease(data []byte) map[string]string {
+	fields := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		fields[key] = value
+	}
+	return fields
+}
@@ -9,12 +9,12 @@ import (
 	"regexp"
 )
 
-func checkOSUpdate() (bool, string, string) {
+func checkOSUpdate(policy Policy) (bool, string, string, *UpdateStatus) {
 	current := getCurrentMacOSVersion()
 	latest := getLatestMacOSVersion()
 
 	isUpToDate := current == latest
-	return isUpToDate, current, latest
+	return isUpToDate, current, latest, nil
 }
 
 func getCurrentMacOSVersion() string {
@@ -4,19 +4,20 @@
 package checks
 
 import (
-	"os/exec"
-	"strings"
 	"bufio"
-	"regexp"
 	"bytes"
+	"os/exec"
+	"strconv"
+	"strings"
 )
 
-func checkOSUpdate() (bool, string, string) {
+func checkOSUpdate(policy Policy) (bool, string, string, *UpdateStatus) {
+	updates := getPendingUpdates(policy)
 	current := getCurrentLinuxVersion()
-	latest := getLatestLinuxVersion()
+	latest := getLatestLinuxVersion(updates)
 
 	isUpToDate := current == latest
-	return isUpToDate, current, latest
+	return isUpToDate, current, latest, updates
 }
 
 func getCurrentLinuxVersion() string {
@@ -66,60 +67,10 @@ func getCurrentLinuxVersion() string {
 	return "unknown"
 }
 
-func getLatestLinuxVersion() string {
-	// Check for available updates based on distribution
-	
-	// Ubuntu/Debian: apt-get update and upgrade -s
-	aptCmd := exec.Command("sh", "-c", "apt-get update -qq && apt-get upgrade -s")
-	out, err := aptCmd.Output()
-	if err == nil {
-		// Count number of lines with "Inst"
-		re := regexp.MustCompile(`(?m)^Inst`)
-		matches := re.FindAllStringIndex(string(out), -1)
-		if len(matches) == 0 {
-			// No updates available, current version is latest
-			return getCurrentLinuxVersion()
-		} else {
-			// Updates available, return count of updates
-			return getCurrentLinuxVersion() + " (Updates available: " + string(rune(len(matches))) + ")"
-		}
-	}
-	
-	// RHEL/CentOS: yum check-update
-	yumCmd := exec.Command("yum", "check-update", "--quiet")
-	err = yumCmd.Run()
-	// yum returns exit code 100 if updates available
-	if err != nil && yumCmd.ProcessState.ExitCode() == 100 {
-		// Updates available
-		return getCurrentLinuxVersion() + " (Updates available)"
-	} else if err == nil {
-		// No updates
-		return getCurrentLinuxVersion()
-	}
-	
-	// Fedora/newer RHEL: dnf check-update
-	dnfCmd := exec.Command("dnf", "check-update", "--quiet")
-	err = dnfCmd.Run()
-	if err != nil && dnfCmd.ProcessState.ExitCode() == 100 {
-		// Updates available
-		return getCurrentLinuxVersion() + " (Updates available)"
-	} else if err == nil {
-		// No updates
+func getLatestLinuxVersion(updates *UpdateStatus) string {
+	// If all update checks fail, return current version
+	if updates == nil || updates.Pending == 0 {
 		return getCurrentLinuxVersion()
 	}
-	
-	// Arch Linux: pacman -Syu --dry-run
-	pacmanCmd := exec.Command("sh", "-c", "pacman -Qu | wc -l")
-	out, err = pacmanCmd.Output()
-	if err == nil {
-		count := strings.TrimSpace(string(out))
-		if count == "0" {
-			return getCurrentLinuxVersion()
-		} else {
-			return getCurrentLinuxVersion() + " (Updates available: " + count + ")"
-		}
-	}
-	
-	// If all update checks fail, return current version
-	return getCurrentLinuxVersion()
-}
\ No newline at end of file
+	return getCurrentLinuxVersion() + " (Updates available: " + strconv.Itoa(updates.Pending) + ")"
+}
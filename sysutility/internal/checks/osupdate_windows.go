@@ -8,12 +8,12 @@ import (
 	"strings"
 )
 
-func checkOSUpdate() (bool, string, string) {
+func checkOSUpdate(policy Policy) (bool, string, string, *UpdateStatus) {
 	current := getCurrentWindowsVersion()
 	latest := getLatestWindowsVersion() // hardcoded or scrape Microsoft API (advanced)
 
 	isUpToDate := current == latest
-	return isUpToDate, current, latest
+	return isUpToDate, current, latest, nil
 }
 
 func getCurrentWindowsVersion() string {
@@ -0,0 +1,129 @@
+//go:build linux
+// +build linux
+
+package checks
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const pamDir = "/etc/pam.d"
+
+// pamRule is one line of a PAM stack with include directives resolved.
+type pamRule struct {
+	Type    string
+	Control string
+	Module  string
+	Args    map[string]string
+}
+
+// pamStack returns the rules of one type (auth, password, ...) that a
+// service runs, following include, substack and @include.
+func pamStack(service, ruleType string) []pamRule {
+	return readPAMFile(filepath.Join(pamDir, service), ruleType, 0)
+}
+
+func readPAMFile(path, ruleType string, depth int) []pamRule {
+	if depth > 8 {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+
+	var rules []pamRule
+	for _, fields := range pamLines(data) {
+		if fields[0] == "@include" {
+			if len(fields) > 1 {
+				rules = append(rules, readPAMFile(pamIncludePath(fields[1]), ruleType, depth+1)...)
+			}
+			continue
+		}
+		if len(fields) < 3 || strings.TrimPrefix(fields[0], "-") != ruleType {
+			continue
+		}
+		if fields[1] == "include" || fields[1] == "substack" {
+			rules = append(rules, readPAMFile(pamIncludePath(fields[2]), ruleType, depth+1)...)
+			continue
+		}
+		rule := pamRule{
+			Type:    ruleType,
+			Control: fields[1],
+			Module:  strings.TrimSuffix(filepath.Base(fields[2]), ".so"),
+			Args:    map[string]string{},
+		}
+		for _, arg := range fields[3:] {
+			key, value, _ := strings.Cut(arg, "=")
+			rule.Args[key] = value
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+func pamIncludePath(name string) string {
+	if filepath.IsAbs(name) {
+		return name
+	}
+	return filepath.Join(pamDir, name)
+}
+
+// pamLines splits a PAM file into fields, joining continuation lines and
+// keeping bracketed controls such as "[success=1 default=ignore]" whole.
+func pamLines(data []byte) [][]string {
+	var lines [][]string
+	var current strings.Builder
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		if strings.HasSuffix(strings.TrimSpace(line), `\`) {
+			current.WriteString(strings.TrimSuffix(strings.TrimSpace(line), `\`) + " ")
+			continue
+		}
+		current.WriteString(line)
+		text := current.String()
+		current.Reset()
+
+		var fields []string
+		for len(strings.TrimSpace(text)) > 0 {
+			text = strings.TrimLeft(text, " \t")
+			if strings.HasPrefix(text, "[") {
+				end := strings.IndexByte(text, ']')
+				if end < 0 {
+					end = len(text) - 1
+				}
+				fields = append(fields, text[:end+1])
+				text = text[end+1:]
+				continue
+			}
+			end := strings.IndexAny(text, " \t")
+			if end < 0 {
+				end = len(text)
+			}
+			fields = append(fields, text[:end])
+			text = text[end:]
+		}
+		if len(fields) > 0 {
+			lines = append(lines, fields)
+		}
+	}
+	return lines
+}
+
+// findPAMModule returns the first rule using one of the modules.
+func findPAMModule(rules []pamRule, modules ...string) *pamRule {
+	for i := range rules {
+		if containsString(modules, rules[i].Module) {
+			return &rules[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package checks
+
+// PasswordPolicyStatus is the effective password and lockout policy.
+type PasswordPolicyStatus struct {
+	// MinLength is the larger of the pam_pwquality/pam_cracklib and
+	// pam_unix minimums.
+	MinLength int `json:"min_length"`
+	// QualityModule is pam_pwquality or pam_cracklib, if configured.
+	QualityModule string `json:"quality_module,omitempty"`
+	// MinClasses is the number of character classes required (minclass).
+	MinClasses int `json:"min_classes,omitempty"`
+	// Credits are the d/u/l/ocredit settings; negative values require that
+	// many characters of the class.
+	DigitCredit int  `json:"dcredit,omitempty"`
+	UpperCredit int  `json:"ucredit,omitempty"`
+	LowerCredit int  `json:"lcredit,omitempty"`
+	OtherCredit int  `json:"ocredit,omitempty"`
+	DictCheck   bool `json:"dict_check"`
+
+	// Password aging from login.defs, in days. -1 means no limit.
+	MaxAgeDays  int `json:"max_age_days"`
+	MinAgeDays  int `json:"min_age_days"`
+	WarnAgeDays int `json:"warn_age_days"`
+
+	// LockoutModule is pam_faillock or pam_tally2, if configured.
+	LockoutModule string `json:"lockout_module,omitempty"`
+	// LockoutDeny is the number of failures before locking; 0 never locks.
+	LockoutDeny            int `json:"lockout_deny"`
+	LockoutUnlockSeconds   int `json:"lockout_unlock_seconds,omitempty"`
+	LockoutIntervalSeconds int `json:"lockout_interval_seconds,omitempty"`
+}
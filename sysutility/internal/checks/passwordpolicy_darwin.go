@@ -0,0 +1,8 @@
+//go:build darwin
+// +build darwin
+
+package checks
+
+func checkPasswordPolicy() *PasswordPolicyStatus {
+	return nil
+}
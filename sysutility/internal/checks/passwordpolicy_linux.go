@@ -0,0 +1,126 @@
+//go:build linux
+// +build linux
+
+package checks
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// pam_unix rejects passwords shorter than this unless minlen= is given.
+const pamUnixDefaultMinLen = 6
+
+func checkPasswordPolicy() *PasswordPolicyStatus {
+	if _, err := os.Stat(pamDir); err != nil {
+		return nil
+	}
+	status := &PasswordPolicyStatus{MaxAgeDays: -1, MinAgeDays: 0, WarnAgeDays: 7}
+
+	if data, err := os.ReadFile("/etc/login.defs"); err == nil {
+		defs := parseLoginDefs(data)
+		status.MaxAgeDays = atoiOr(defs["PASS_MAX_DAYS"], status.MaxAgeDays)
+		status.MinAgeDays = atoiOr(defs["PASS_MIN_DAYS"], status.MinAgeDays)
+		status.WarnAgeDays = atoiOr(defs["PASS_WARN_AGE"], status.WarnAgeDays)
+		// 99999 is how distributions spell "never expires"
+		if status.MaxAgeDays >= 99999 {
+			status.MaxAgeDays = -1
+		}
+	}
+
+	passwordRules := pamStack("passwd", "password")
+	status.MinLength = pamUnixDefaultMinLen
+	if unix := findPAMModule(passwordRules, "pam_unix"); unix != nil {
+		status.MinLength = atoiOr(unix.Args["minlen"], status.MinLength)
+	}
+	if quality := findPAMModule(passwordRules, "pam_pwquality", "pam_cracklib"); quality != nil {
+		applyQualityPolicy(status, quality)
+	}
+
+	// Lockout happens in the auth stack; login and sshd usually share it
+	authRules := pamStack("login", "auth")
+	if len(authRules) == 0 {
+		authRules = pamStack("sshd", "auth")
+	}
+	if lock := findPAMModule(authRules, "pam_faillock", "pam_tally2"); lock != nil {
+		applyLockoutPolicy(status, lock)
+	}
+	return status
+}
+
+// applyQualityPolicy merges pwquality.conf (and its .d directory) with the
+// module arguments, which take precedence. pam_cracklib has no config file.
+func applyQualityPolicy(status *PasswordPolicyStatus, rule *pamRule) {
+	status.QualityModule = rule.Module
+	settings := map[string]string{"minlen": "9", "dictcheck": "1"}
+	if rule.Module == "pam_pwquality" {
+		settings["minlen"] = "8"
+		files := []string{"/etc/security/pwquality.conf"}
+		dropIns, _ := filepath.Glob("/etc/security/pwquality.conf.d/*.conf")
+		sort.Strings(dropIns)
+		for _, file := range append(files, dropIns...) {
+			if data, err := os.ReadFile(file); err == nil {
+				for key, value := range parseINI(data)[""] {
+					settings[key] = value
+				}
+			}
+		}
+	}
+	for key, value := range rule.Args {
+		settings[key] = value
+	}
+
+	if minLen := atoiOr(settings["minlen"], 0); minLen > status.MinLength {
+		status.MinLength = minLen
+	}
+	status.MinClasses = atoiOr(settings["minclass"], 0)
+	status.DigitCredit = atoiOr(settings["dcredit"], 0)
+	status.UpperCredit = atoiOr(settings["ucredit"], 0)
+	status.LowerCredit = atoiOr(settings["lcredit"], 0)
+	status.OtherCredit = atoiOr(settings["ocredit"], 0)
+	status.DictCheck = settings["dictcheck"] != "0"
+}
+
+// applyLockoutPolicy reads pam_faillock (faillock.conf, overridden by
+// arguments) or pam_tally2 (arguments only, no lockout by default).
+func applyLockoutPolicy(status *PasswordPolicyStatus, rule *pamRule) {
+	status.LockoutModule = rule.Module
+	settings := map[string]string{}
+	if rule.Module == "pam_faillock" {
+		settings = map[string]string{"deny": "3", "fail_interval": "900", "unlock_time": "600"}
+		if data, err := os.ReadFile("/etc/security/faillock.conf"); err == nil {
+			for key, value := range parseINI(data)[""] {
+				settings[key] = value
+			}
+		}
+	}
+	for key, value := range rule.Args {
+		settings[key] = value
+	}
+	status.LockoutDeny = atoiOr(settings["deny"], 0)
+	status.LockoutUnlockSeconds = atoiOr(settings["unlock_time"], 0)
+	status.LockoutIntervalSeconds = atoiOr(settings["fail_interval"], 0)
+}
+
+// parseLoginDefs reads "KEY value" lines of login.defs.
+func parseLoginDefs(data []byte) map[string]string {
+	defs := map[string]string{}
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 2 && !strings.HasPrefix(fields[0], "#") {
+			defs[fields[0]] = fields[1]
+		}
+	}
+	return defs
+}
+
+func atoiOr(s string, fallback int) int {
+	n, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil {
+		return fallback
+	}
+	return n
+}
@@ -0,0 +1,51 @@
+package checks
+
+// Policy holds operator-configured settings that change how checks judge a
+// machine. It is stored in the agent config file next to the interval.
+type Policy struct {
+	// FlagNonLoopbackListeners marks every listening socket reachable from
+	// the network, for machines that are not meant to serve anything.
+	FlagNonLoopbackListeners bool `json:"flag_non_loopback_listeners,omitempty"`
+
+	// ScreenLockMinutes is the longest idle time allowed before the screen
+	// locks. Zero means the default of 10 minutes.
+	ScreenLockMinutes int `json:"screen_lock_minutes,omitempty"`
+
+	// UpdateIndexRefreshHours lets the agent refresh package indices (e.g.
+	// apt-get update) once they are older than this. Zero never refreshes.
+	UpdateIndexRefreshHours int `json:"update_index_refresh_hours,omitempty"`
+
+	// AdvisoryDir holds OSV JSON files or OVAL XML exports to match installed
+	// packages against. Empty means /var/lib/sysutility/advisories.
+	AdvisoryDir string `json:"advisory_dir,omitempty"`
+
+	// EOLTablePath points at a JSON end-of-life table that replaces the one
+	// bundled with the agent, so new dates can ship without a rebuild.
+	EOLTablePath string `json:"eol_table_path,omitempty"`
+
+	// AllowExtendedSupport counts releases covered only by extended support
+	// (Ubuntu ESM, Debian LTS, RHEL ELS) as up to date, for fleets that
+	// are enrolled in those programs.
+	AllowExtendedSupport bool `json:"allow_extended_support,omitempty"`
+
+	// AVSignatureMaxAgeHours is how old antivirus signatures may get before
+	// the antivirus no longer counts as active. Zero means 48 hours.
+	AVSignatureMaxAgeHours int `json:"av_signature_max_age_hours,omitempty"`
+
+	// SysctlBaseline adds to or overrides the built-in kernel parameter
+	// baseline, e.g. {"net.ipv4.ip_forward": "1"} on a router. An empty
+	// value drops the parameter from the baseline.
+	SysctlBaseline map[string]string `json:"sysctl_baseline,omitempty"`
+
+	// RequireLogForwarding fails the logging check on hosts that do not
+	// ship their logs to a remote collector.
+	RequireLogForwarding bool `json:"require_log_forwarding,omitempty"`
+
+	// RequireAuditd fails the logging check on hosts where auditd is not
+	// running. Debian and Ubuntu do not install it by default.
+	RequireAuditd bool `json:"require_auditd,omitempty"`
+
+	// BlockUSBStorage fails the USB check unless mass storage devices are
+	// blocked, for machine classes under data-loss prevention rules.
+	BlockUSBStorage bool `json:"block_usb_storage,omitempty"`
+}
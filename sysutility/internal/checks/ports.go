@@ -0,0 +1,33 @@
+package checks
+
+// ListeningSocket is a socket accepting connections (TCP) or datagrams (UDP)
+// and the process that owns it.
+type ListeningSocket struct {
+	Protocol   string `json:"protocol"`
+	Address    string `json:"address"`
+	Port       int    `json:"port"`
+	PID        int    `json:"pid,omitempty"`
+	Executable string `json:"executable,omitempty"`
+	Unit       string `json:"unit,omitempty"`
+
+	// Exposed is true when the socket is bound to a non-loopback address.
+	Exposed bool `json:"exposed"`
+	// Flagged is set when the configured policy disallows the socket.
+	Flagged bool `json:"flagged,omitempty"`
+}
+
+// listenersChanged compares two socket inventories ignoring PIDs, which
+// change on every service restart.
+func listenersChanged(old, new []ListeningSocket) bool {
+	if len(old) != len(new) {
+		return true
+	}
+	for i := range old {
+		a, b := old[i], new[i]
+		a.PID, b.PID = 0, 0
+		if a != b {
+			return true
+		}
+	}
+	return false
+}
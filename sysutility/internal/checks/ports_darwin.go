@@ -0,0 +1,9 @@
+//go:build darwin
+// +build darwin
+
+package checks
+
+func checkListeningPorts(policy Policy) []ListeningSocket {
+	// Not implemented yet
+	return nil
+}
@@ -0,0 +1,189 @@
+//go:build linux
+// +build linux
+
+package checks
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	tcpListen = "0A"
+	udpClose  = "07"
+)
+
+func checkListeningPorts(policy Policy) []ListeningSocket {
+	var sockets []ListeningSocket
+	inodes := map[string]int{}
+
+	ephemeralLow, ephemeralHigh := ephemeralPortRange()
+	for _, proto := range []string{"tcp", "tcp6", "udp", "udp6"} {
+		data, err := os.ReadFile(filepath.Join("/proc/net", proto))
+		if err != nil {
+			continue
+		}
+		for _, entry := range parseProcNet(data, proto) {
+			// Unconnected UDP clients (resolvers, NTP queries) get an
+			// ephemeral port and come and go between runs
+			if strings.HasPrefix(proto, "udp") && entry.socket.Port >= ephemeralLow && entry.socket.Port <= ephemeralHigh {
+				continue
+			}
+			inodes[entry.inode] = len(sockets)
+			sockets = append(sockets, entry.socket)
+		}
+	}
+
+	// Attribute each socket inode to the process holding it open
+	pids, _ := filepath.Glob("/proc/[0-9]*")
+	for _, procDir := range pids {
+		pid, err := strconv.Atoi(filepath.Base(procDir))
+		if err != nil {
+			continue
+		}
+		fds, err := os.ReadDir(filepath.Join(procDir, "fd"))
+		if err != nil {
+			continue
+		}
+		for _, fd := range fds {
+			link, err := os.Readlink(filepath.Join(procDir, "fd", fd.Name()))
+			if err != nil || !strings.HasPrefix(link, "socket:[") {
+				continue
+			}
+			idx, ok := inodes[strings.TrimSuffix(strings.TrimPrefix(link, "socket:["), "]")]
+			if !ok || sockets[idx].PID != 0 {
+				continue
+			}
+			sockets[idx].PID = pid
+			sockets[idx].Executable, _ = os.Readlink(filepath.Join(procDir, "exe"))
+			if cgroup, err := os.ReadFile(filepath.Join(procDir, "cgroup")); err == nil {
+				sockets[idx].Unit = parseSystemdUnit(cgroup)
+			}
+		}
+	}
+
+	for i := range sockets {
+		sockets[i].Flagged = policy.FlagNonLoopbackListeners && sockets[i].Exposed
+	}
+
+	// Order fully so SO_REUSEPORT workers sharing an address keep their
+	// places between runs
+	sort.Slice(sockets, func(i, j int) bool {
+		a, b := sockets[i], sockets[j]
+		if a.Protocol != b.Protocol {
+			return a.Protocol < b.Protocol
+		}
+		if a.Port != b.Port {
+			return a.Port < b.Port
+		}
+		if a.Address != b.Address {
+			return a.Address < b.Address
+		}
+		if a.Executable != b.Executable {
+			return a.Executable < b.Executable
+		}
+		if a.Unit != b.Unit {
+			return a.Unit < b.Unit
+		}
+		return a.PID < b.PID
+	})
+	return sockets
+}
+
+// ephemeralPortRange reads the range the kernel assigns client ports from.
+func ephemeralPortRange() (int, int) {
+	fields := strings.Fields(string(readFileOrNil("/proc/sys/net/ipv4/ip_local_port_range")))
+	if len(fields) != 2 {
+		return 32768, 60999
+	}
+	return atoiOr(fields[0], 32768), atoiOr(fields[1], 60999)
+}
+
+type procNetEntry struct {
+	socket ListeningSocket
+	inode  string
+}
+
+// parseProcNet returns the listening sockets in a /proc/net/{tcp,udp}[6]
+// table. For UDP, unconnected sockets with a bound port are reported.
+func parseProcNet(data []byte, proto string) []procNetEntry {
+	var entries []procNetEntry
+	wantState := tcpListen
+	if strings.HasPrefix(proto, "udp") {
+		wantState = udpClose
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Scan() // header
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 10 || fields[3] != wantState {
+			continue
+		}
+		// UDP sockets in CLOSE state with a remote peer are connected clients
+		if wantState == udpClose && !strings.HasSuffix(fields[2], ":0000") {
+			continue
+		}
+
+		ip, port, err := parseProcNetAddr(fields[1])
+		if err != nil || port == 0 {
+			continue
+		}
+		entries = append(entries, procNetEntry{
+			socket: ListeningSocket{
+				Protocol: proto,
+				Address:  ip.String(),
+				Port:     port,
+				Exposed:  !ip.IsLoopback(),
+			},
+			inode: fields[9],
+		})
+	}
+	return entries
+}
+
+// parseProcNetAddr decodes "0100007F:0277". The address is stored as 32-bit
+// words in host byte order, the port in network byte order.
+func parseProcNetAddr(s string) (net.IP, int, error) {
+	addrHex, portHex, _ := strings.Cut(s, ":")
+	raw, err := hex.DecodeString(addrHex)
+	if err != nil {
+		return nil, 0, err
+	}
+	port, err := strconv.ParseUint(portHex, 16, 16)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	ip := make(net.IP, len(raw))
+	for i := 0; i+4 <= len(raw); i += 4 {
+		ip[i], ip[i+1], ip[i+2], ip[i+3] = raw[i+3], raw[i+2], raw[i+1], raw[i]
+	}
+	return ip, int(port), nil
+}
+
+// parseSystemdUnit extracts the service or scope from /proc/<pid>/cgroup,
+// e.g. "0::/system.slice/sshd.service" -> "sshd.service".
+func parseSystemdUnit(cgroup []byte) string {
+	scanner := bufio.NewScanner(bytes.NewReader(cgroup))
+	for scanner.Scan() {
+		parts := strings.SplitN(scanner.Text(), ":", 3)
+		if len(parts) != 3 || (parts[0] != "0" && !strings.Contains(parts[1], "systemd")) {
+			continue
+		}
+		segments := strings.Split(parts[2], "/")
+		for i := len(segments) - 1; i >= 0; i-- {
+			if strings.HasSuffix(segments[i], ".service") || strings.HasSuffix(segments[i], ".scope") {
+				return segments[i]
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,9 @@
+//go:build windows
+// +build windows
+
+package checks
+
+func checkListeningPorts(policy Policy) []ListeningSocket {
+	// Not implemented yet
+	return nil
+}
@@ -0,0 +1,28 @@
+package checks
+
+// RebootStatus reports updates that are installed but not yet in use.
+type RebootStatus struct {
+	// Required is set when the distribution asks for a reboot or the
+	// running kernel is older than the newest installed one.
+	Required bool `json:"required"`
+	// Packages lists what triggered /var/run/reboot-required, if known.
+	Packages []string `json:"packages,omitempty"`
+
+	RunningKernel string `json:"running_kernel,omitempty"`
+	NewestKernel  string `json:"newest_kernel,omitempty"`
+	StaleKernel   bool   `json:"stale_kernel"`
+
+	// StaleProcesses still map shared libraries that were deleted or
+	// replaced on disk, so they run the old, possibly vulnerable, code.
+	StaleProcesses []StaleProcess `json:"stale_processes,omitempty"`
+	// ServicesToRestart are the systemd services owning those processes.
+	ServicesToRestart []string `json:"services_to_restart,omitempty"`
+}
+
+// StaleProcess groups the processes of one executable and unit. PIDs are
+// left out so worker churn does not count as a change.
+type StaleProcess struct {
+	Executable string   `json:"executable"`
+	Unit       string   `json:"unit,omitempty"`
+	Libraries  []string `json:"libraries"`
+}
@@ -0,0 +1,9 @@
+//go:build darwin
+// +build darwin
+
+package checks
+
+func checkPendingReboot() *RebootStatus {
+	// softwareupdate restarts the machine itself when an update needs it
+	return nil
+}
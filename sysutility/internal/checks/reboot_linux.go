@@ -0,0 +1,156 @@
+//go:build linux
+// +build linux
+
+package checks
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+func checkPendingReboot() *RebootStatus {
+	status := &RebootStatus{}
+
+	if _, err := os.Stat("/var/run/reboot-required"); err == nil {
+		status.Required = true
+		if data, err := os.ReadFile("/var/run/reboot-required.pkgs"); err == nil {
+			status.Packages = uniqueLines(data)
+		}
+	}
+
+	if data, err := os.ReadFile("/proc/sys/kernel/osrelease"); err == nil {
+		status.RunningKernel = strings.TrimSpace(string(data))
+	}
+	status.NewestKernel = newestInstalledKernel("/lib/modules")
+	if status.RunningKernel != "" && status.NewestKernel != "" &&
+		rpmVerCmp(status.NewestKernel, status.RunningKernel) > 0 {
+		status.StaleKernel = true
+		status.Required = true
+	}
+
+	status.StaleProcesses = findStaleProcesses()
+	services := map[string]bool{}
+	for _, p := range status.StaleProcesses {
+		if strings.HasSuffix(p.Unit, ".service") {
+			services[p.Unit] = true
+		}
+	}
+	for unit := range services {
+		status.ServicesToRestart = append(status.ServicesToRestart, unit)
+	}
+	sort.Strings(status.ServicesToRestart)
+	return status
+}
+
+// newestInstalledKernel returns the newest kernel release with modules
+// installed. Every distribution's kernel package ships a
+// /lib/modules/<release>/kernel tree; directories left behind by removed
+// kernels lack it.
+func newestInstalledKernel(modulesDir string) string {
+	dirs, _ := filepath.Glob(filepath.Join(modulesDir, "*", "kernel"))
+	newest := ""
+	for _, dir := range dirs {
+		release := filepath.Base(filepath.Dir(dir))
+		if newest == "" || rpmVerCmp(release, newest) > 0 {
+			newest = release
+		}
+	}
+	return newest
+}
+
+// findStaleProcesses scans /proc/*/maps for shared objects marked
+// "(deleted)". Processes we may not read (other users when not root) are
+// skipped.
+func findStaleProcesses() []StaleProcess {
+	groups := map[string]*StaleProcess{}
+	procDirs, _ := filepath.Glob("/proc/[0-9]*")
+	for _, procDir := range procDirs {
+		maps, err := os.ReadFile(filepath.Join(procDir, "maps"))
+		if err != nil {
+			continue
+		}
+		libs := parseDeletedMappings(maps)
+		if len(libs) == 0 {
+			continue
+		}
+
+		exe, _ := os.Readlink(filepath.Join(procDir, "exe"))
+		exe = strings.TrimSuffix(exe, " (deleted)")
+		unit := ""
+		if cgroup, err := os.ReadFile(filepath.Join(procDir, "cgroup")); err == nil {
+			unit = parseSystemdUnit(cgroup)
+		}
+
+		key := exe + "|" + unit
+		group, ok := groups[key]
+		if !ok {
+			group = &StaleProcess{Executable: exe, Unit: unit}
+			groups[key] = group
+		}
+		for _, lib := range libs {
+			if !containsString(group.Libraries, lib) {
+				group.Libraries = append(group.Libraries, lib)
+			}
+		}
+	}
+
+	var stale []StaleProcess
+	for _, group := range groups {
+		sort.Strings(group.Libraries)
+		stale = append(stale, *group)
+	}
+	sort.Slice(stale, func(i, j int) bool {
+		if stale[i].Executable != stale[j].Executable {
+			return stale[i].Executable < stale[j].Executable
+		}
+		return stale[i].Unit < stale[j].Unit
+	})
+	return stale
+}
+
+// parseDeletedMappings returns the deleted shared objects in a maps file,
+// e.g. "7f1c... r-xp 00000000 fd:01 1234 /usr/lib/x86_64-linux-gnu/libssl.so.3 (deleted)".
+// Deleted temporary files, memfds and shared memory are not library
+// updates and are ignored.
+func parseDeletedMappings(data []byte) []string {
+	var libs []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasSuffix(line, " (deleted)") {
+			continue
+		}
+		fields := strings.Fields(strings.TrimSuffix(line, " (deleted)"))
+		if len(fields) < 6 {
+			continue
+		}
+		path := strings.Join(fields[5:], " ")
+		if !strings.HasPrefix(path, "/") || !strings.Contains(filepath.Base(path), ".so") {
+			continue
+		}
+		if strings.HasPrefix(path, "/tmp/") || strings.HasPrefix(path, "/dev/shm/") || strings.HasPrefix(path, "/memfd:") {
+			continue
+		}
+		if !containsString(libs, path) {
+			libs = append(libs, path)
+		}
+	}
+	return libs
+}
+
+// uniqueLines returns the distinct non-empty lines of data in order.
+func uniqueLines(data []byte) []string {
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !containsString(lines, line) {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
@@ -0,0 +1,23 @@
+//go:build windows
+// +build windows
+
+package checks
+
+import "os/exec"
+
+// Registry keys Windows creates while an installed update waits for a
+// reboot. `reg query` exits non-zero when the key is absent.
+var rebootPendingKeys = []string{
+	`HKLM\SOFTWARE\Microsoft\Windows\CurrentVersion\WindowsUpdate\Auto Update\RebootRequired`,
+	`HKLM\SOFTWARE\Microsoft\Windows\CurrentVersion\Component Based Servicing\RebootPending`,
+}
+
+func checkPendingReboot() *RebootStatus {
+	status := &RebootStatus{}
+	for _, key := range rebootPendingKeys {
+		if err := exec.Command("reg", "query", key).Run(); err == nil {
+			status.Required = true
+		}
+	}
+	return status
+}
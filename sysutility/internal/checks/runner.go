@@ -1,10 +1,47 @@
 package checks
 
-func RunAllChecks() SystemReport {
+import (
+	"reflect"
+	"strings"
+
+	"sysutility/internal/inventory"
+)
+
+// RunAllChecks runs every check. packages is the software inventory the
+// caller collected for this cycle; checks that need it share that copy.
+func RunAllChecks(policy Policy, packages []inventory.Package) SystemReport {
 	diskEncrypted, method := checkDiskEncryption()
-	osUpToDate, current, latest := checkOSUpdate()
-	avExists, avActive, avName := checkAntivirus()
-	sleep := checkSleepSettings()
+	osUpToDate, current, latest, osUpdates := checkOSUpdate(policy)
+	eol := checkEOL(policy)
+	// A release past standard support is not up to date unless the fleet
+	// pays for extended support
+	if eol != nil && (eol.Status == "eol" || eol.Status == "extended_support" && !policy.AllowExtendedSupport) {
+		osUpToDate = false
+	}
+	reboot := checkPendingReboot()
+	autoUpdates := checkAutoUpdates()
+	avExists, avActive, avName, avProducts := checkAntivirus()
+	clamAV := checkClamAV(policy)
+	// An installed ClamAV only protects with clamd and fresh signatures
+	if clamAV != nil && strings.Contains(strings.ToLower(avName), "clam") {
+		avActive = clamAV.Active()
+	}
+	sleep, sleepUsers := checkSleepSettings()
+	logind := checkLogind()
+	loggingOK, logging := checkLogging(policy)
+	timeSync := checkTimeSync()
+	tpm := checkTPM()
+	firewall := checkFirewall()
+	ports := checkListeningPorts(policy)
+	mac := checkMAC(ports)
+	sysctl := checkSysctl(policy)
+	mounts := checkMounts()
+	usb := checkUSB(policy)
+	ssh := checkSSH()
+	accounts := checkAccounts()
+	passwordPolicy := checkPasswordPolicy()
+	screenLock := checkScreenLock(policy)
+	vulnerabilities := checkVulnerabilities(policy, packages)
 
 	return SystemReport{
 		DiskEncrypted:        diskEncrypted,
@@ -12,10 +49,33 @@ func RunAllChecks() SystemReport {
 		OSUpToDate:           osUpToDate,
 		CurrentVersion:       current,
 		LatestVersion:        latest,
+		OSUpdates:            osUpdates,
+		EOL:                  eol,
+		Reboot:               reboot,
+		AutoUpdates:          autoUpdates,
 		AntivirusExists:      avExists,
 		AntivirusActive:      avActive,
 		AntivirusName:        avName,
+		AntivirusProducts:    avProducts,
+		ClamAV:               clamAV,
 		SleepOK:              sleep,
+		SleepUsers:           sleepUsers,
+		Logind:               logind,
+		LoggingOK:            loggingOK,
+		Logging:              logging,
+		TimeSync:             timeSync,
+		TPM:                  tpm,
+		Firewall:             firewall,
+		ListeningPorts:       ports,
+		MAC:                  mac,
+		Sysctl:               sysctl,
+		Mounts:               mounts,
+		USB:                  usb,
+		SSH:                  ssh,
+		Accounts:             accounts,
+		PasswordPolicy:       passwordPolicy,
+		ScreenLock:           screenLock,
+		Vulnerabilities:      vulnerabilities,
 	}
 }
 
@@ -24,8 +84,31 @@ func HasChangedFrom(oldReport, newReport SystemReport) bool {
 		oldReport.OSUpToDate != newReport.OSUpToDate ||
 		oldReport.CurrentVersion != newReport.CurrentVersion ||
 		oldReport.LatestVersion != newReport.LatestVersion ||
+		updatesChanged(oldReport.OSUpdates, newReport.OSUpdates) ||
+		!reflect.DeepEqual(oldReport.EOL, newReport.EOL) ||
+		!reflect.DeepEqual(oldReport.Reboot, newReport.Reboot) ||
+		autoUpdatesChanged(oldReport.AutoUpdates, newReport.AutoUpdates) ||
 		oldReport.AntivirusExists != newReport.AntivirusExists ||
 		oldReport.AntivirusActive != newReport.AntivirusActive ||
 		oldReport.AntivirusName != newReport.AntivirusName ||
-		oldReport.SleepOK != newReport.SleepOK
+		!reflect.DeepEqual(oldReport.AntivirusProducts, newReport.AntivirusProducts) ||
+		clamAVChanged(oldReport.ClamAV, newReport.ClamAV) ||
+		oldReport.SleepOK != newReport.SleepOK ||
+		!reflect.DeepEqual(oldReport.SleepUsers, newReport.SleepUsers) ||
+		!reflect.DeepEqual(oldReport.Logind, newReport.Logind) ||
+		oldReport.LoggingOK != newReport.LoggingOK ||
+		loggingChanged(oldReport.Logging, newReport.Logging) ||
+		timeSyncChanged(oldReport.TimeSync, newReport.TimeSync) ||
+		tpmChanged(oldReport.TPM, newReport.TPM) ||
+		!reflect.DeepEqual(oldReport.Firewall, newReport.Firewall) ||
+		listenersChanged(oldReport.ListeningPorts, newReport.ListeningPorts) ||
+		!reflect.DeepEqual(oldReport.MAC, newReport.MAC) ||
+		!reflect.DeepEqual(oldReport.Sysctl, newReport.Sysctl) ||
+		!reflect.DeepEqual(oldReport.Mounts, newReport.Mounts) ||
+		!reflect.DeepEqual(oldReport.USB, newReport.USB) ||
+		!reflect.DeepEqual(oldReport.SSH, newReport.SSH) ||
+		!reflect.DeepEqual(oldReport.Accounts, newReport.Accounts) ||
+		!reflect.DeepEqual(oldReport.PasswordPolicy, newReport.PasswordPolicy) ||
+		!reflect.DeepEqual(oldReport.ScreenLock, newReport.ScreenLock) ||
+		!reflect.DeepEqual(oldReport.Vulnerabilities, newReport.Vulnerabilities)
 }
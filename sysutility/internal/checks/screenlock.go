@@ -0,0 +1,22 @@
+package checks
+
+// defaultScreenLockMinutes is used when the policy does not set a limit.
+const defaultScreenLockMinutes = 10
+
+// ScreenLockStatus reports whether every graphical user's session locks
+// after being idle, independent of whether the machine suspends.
+type ScreenLockStatus struct {
+	MaxIdleMinutes int              `json:"max_idle_minutes"`
+	Users          []UserScreenLock `json:"users,omitempty"`
+	Compliant      bool             `json:"compliant"`
+}
+
+type UserScreenLock struct {
+	User        string `json:"user"`
+	Desktop     string `json:"desktop,omitempty"`
+	Method      string `json:"method,omitempty"`
+	LockEnabled bool   `json:"lock_enabled"`
+	// LockAfterSeconds is the idle time before the screen locks; 0 means never.
+	LockAfterSeconds int  `json:"lock_after_seconds"`
+	Compliant        bool `json:"compliant"`
+}
@@ -0,0 +1,9 @@
+//go:build darwin
+// +build darwin
+
+package checks
+
+func checkScreenLock(policy Policy) *ScreenLockStatus {
+	// Not implemented yet
+	return nil
+}
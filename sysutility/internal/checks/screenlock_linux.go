@@ -0,0 +1,275 @@
+//go:build linux
+// +build linux
+
+package checks
+
+import (
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+func checkScreenLock(policy Policy) *ScreenLockStatus {
+	sessions := graphicalUsers()
+	if len(sessions) == 0 {
+		// Headless machine or nobody logged in: nothing to evaluate
+		return nil
+	}
+
+	maxMinutes := policy.ScreenLockMinutes
+	if maxMinutes <= 0 {
+		maxMinutes = defaultScreenLockMinutes
+	}
+
+	status := &ScreenLockStatus{MaxIdleMinutes: maxMinutes, Compliant: true}
+	for _, session := range sessions {
+		lock := getUserScreenLock(session)
+		lock.Compliant = lock.LockEnabled && lock.LockAfterSeconds > 0 && lock.LockAfterSeconds <= maxMinutes*60
+		if !lock.Compliant {
+			status.Compliant = false
+		}
+		status.Users = append(status.Users, lock)
+	}
+	return status
+}
+
+func getUserScreenLock(session loginSession) UserScreenLock {
+	lock := UserScreenLock{User: session.User, Desktop: session.Desktop}
+	desktop := strings.ToLower(session.Desktop)
+
+	var probes []func(loginSession) (UserScreenLock, bool)
+	switch {
+	case strings.Contains(desktop, "kde") || strings.Contains(desktop, "plasma"):
+		probes = append(probes, kdeScreenLock)
+	case strings.Contains(desktop, "xfce"):
+		probes = append(probes, xfceScreenLock, lightLockerScreenLock, xscreensaverLock)
+	case isGNOMEDesktop(desktop):
+		probes = append(probes, gnomeScreenLock)
+	default:
+		probes = append(probes, gnomeScreenLock, xfceScreenLock, lightLockerScreenLock, xscreensaverLock)
+	}
+
+	for _, probe := range probes {
+		if found, ok := probe(session); ok {
+			found.User = lock.User
+			found.Desktop = lock.Desktop
+			return found
+		}
+	}
+	return lock
+}
+
+// gnomeScreenLock reads org.gnome.desktop.* through gsettings as the user.
+// The screen locks lock-delay seconds after the session goes idle.
+func gnomeScreenLock(session loginSession) (UserScreenLock, bool) {
+	get := func(schema, key string) (string, bool) {
+		cmd := userCommand(session.UID, session.User, session.Home, "gsettings", "get", schema, key)
+		if cmd == nil {
+			return "", false
+		}
+		out, err := cmd.Output()
+		if err != nil {
+			return "", false
+		}
+		return strings.TrimSpace(string(out)), true
+	}
+
+	enabled, ok := get("org.gnome.desktop.screensaver", "lock-enabled")
+	if !ok {
+		return UserScreenLock{}, false
+	}
+	idle, _ := get("org.gnome.desktop.session", "idle-delay")
+	delay, _ := get("org.gnome.desktop.screensaver", "lock-delay")
+
+	lock := UserScreenLock{Method: "gnome", LockEnabled: enabled == "true"}
+	if idleSecs := parseGVariantUint(idle); idleSecs > 0 && lock.LockEnabled {
+		lock.LockAfterSeconds = idleSecs + parseGVariantUint(delay)
+	}
+	return lock, true
+}
+
+// parseGVariantUint parses gsettings output such as "uint32 300".
+func parseGVariantUint(value string) int {
+	fields := strings.Fields(value)
+	if len(fields) == 0 {
+		return 0
+	}
+	n, _ := strconv.Atoi(fields[len(fields)-1])
+	return n
+}
+
+// kdeScreenLock reads kscreenlockerrc. KDE locks after 5 minutes by
+// default, so a missing file still means the lock is on.
+func kdeScreenLock(session loginSession) (UserScreenLock, bool) {
+	lock := UserScreenLock{Method: "kde", LockEnabled: true, LockAfterSeconds: 5 * 60}
+
+	data, err := os.ReadFile(filepath.Join(session.Home, ".config", "kscreenlockerrc"))
+	if err != nil {
+		return lock, true
+	}
+	daemon := parseINI(data)["Daemon"]
+	if daemon["Autolock"] == "false" {
+		lock.LockEnabled = false
+		lock.LockAfterSeconds = 0
+		return lock, true
+	}
+	if minutes, err := strconv.Atoi(daemon["Timeout"]); err == nil {
+		lock.LockAfterSeconds = minutes * 60
+	}
+	if grace, err := strconv.Atoi(daemon["LockGrace"]); err == nil && lock.LockAfterSeconds > 0 {
+		lock.LockAfterSeconds += grace
+	}
+	return lock, true
+}
+
+// xfceScreenLock reads the xfce4-screensaver channel. Delays are minutes.
+func xfceScreenLock(session loginSession) (UserScreenLock, bool) {
+	props, err := readXfconfChannel(session.Home, "xfce4-screensaver")
+	if err != nil {
+		return UserScreenLock{}, false
+	}
+
+	lock := UserScreenLock{Method: "xfce4-screensaver"}
+	lock.LockEnabled = props["/saver/enabled"] != "false" && props["/lock/enabled"] != "false"
+	if !lock.LockEnabled {
+		return lock, true
+	}
+
+	idle := 5
+	if v, err := strconv.Atoi(props["/saver/idle-activation/delay"]); err == nil {
+		idle = v
+	}
+	after, _ := strconv.Atoi(props["/lock/saver-activation/delay"])
+	if idle > 0 {
+		lock.LockAfterSeconds = (idle + after) * 60
+	}
+	return lock, true
+}
+
+// lightLockerScreenLock handles light-locker, which locks when the X
+// screensaver (blanking) kicks in, optionally after a grace period.
+func lightLockerScreenLock(session loginSession) (UserScreenLock, bool) {
+	if !userProcessRunning(session.UID, "light-locker") {
+		return UserScreenLock{}, false
+	}
+	lock := UserScreenLock{Method: "light-locker", LockEnabled: true}
+
+	blankMinutes := 0
+	if props, err := readXfconfChannel(session.Home, "xfce4-power-manager"); err == nil {
+		blankMinutes, _ = strconv.Atoi(props["/xfce4-power-manager/blank-on-ac"])
+	}
+	if blankMinutes <= 0 {
+		// Without blanking the screensaver never activates
+		return lock, true
+	}
+	lock.LockAfterSeconds = blankMinutes * 60
+
+	if cmd := userCommand(session.UID, session.User, session.Home, "gsettings", "get", "apps.light-locker", "late-locking"); cmd != nil {
+		if out, err := cmd.Output(); err == nil && strings.TrimSpace(string(out)) == "true" {
+			if cmd := userCommand(session.UID, session.User, session.Home, "gsettings", "get", "apps.light-locker", "lock-after-screensaver"); cmd != nil {
+				if out, err := cmd.Output(); err == nil {
+					lock.LockAfterSeconds += parseGVariantUint(strings.TrimSpace(string(out)))
+				}
+			}
+		}
+	}
+	return lock, true
+}
+
+// xscreensaverLock reads ~/.xscreensaver, where times are "H:MM:SS".
+func xscreensaverLock(session loginSession) (UserScreenLock, bool) {
+	data, err := os.ReadFile(filepath.Join(session.Home, ".xscreensaver"))
+	if err != nil {
+		return UserScreenLock{}, false
+	}
+
+	settings := map[string]string{}
+	for _, line := range strings.Split(string(data), "\n") {
+		if key, value, ok := strings.Cut(line, ":"); ok {
+			settings[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+
+	lock := UserScreenLock{Method: "xscreensaver", LockEnabled: strings.EqualFold(settings["lock"], "true")}
+	if lock.LockEnabled {
+		timeout := parseClockDuration(settings["timeout"])
+		if timeout > 0 {
+			lock.LockAfterSeconds = timeout + parseClockDuration(settings["lockTimeout"])
+		}
+	}
+	return lock, true
+}
+
+// parseClockDuration converts "H:MM:SS" to seconds.
+func parseClockDuration(value string) int {
+	total := 0
+	for _, part := range strings.Split(value, ":") {
+		n, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			return 0
+		}
+		total = total*60 + n
+	}
+	return total
+}
+
+// userProcessRunning looks for a process by comm name owned by uid, or by
+// anyone when uid is negative.
+func userProcessRunning(uid int, name string) bool {
+	procs, _ := filepath.Glob("/proc/[0-9]*")
+	for _, proc := range procs {
+		comm, err := os.ReadFile(filepath.Join(proc, "comm"))
+		if err != nil || strings.TrimSpace(string(comm)) != name {
+			continue
+		}
+		if uid < 0 {
+			return true
+		}
+		if info, err := os.Stat(proc); err == nil && statUID(info) == uid {
+			return true
+		}
+	}
+	return false
+}
+
+// readXfconfChannel loads a per-user xfconf channel as a map of property
+// paths (e.g. "/lock/enabled") to values.
+func readXfconfChannel(home, channel string) (map[string]string, error) {
+	path := filepath.Join(home, ".config", "xfce4", "xfconf", "xfce-perchannel-xml", channel+".xml")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseXfconf(data)
+}
+
+type xfconfProperty struct {
+	Name       string           `xml:"name,attr"`
+	Value      string           `xml:"value,attr"`
+	Properties []xfconfProperty `xml:"property"`
+}
+
+func parseXfconf(data []byte) (map[string]string, error) {
+	var channel struct {
+		Properties []xfconfProperty `xml:"property"`
+	}
+	if err := xml.Unmarshal(data, &channel); err != nil {
+		return nil, err
+	}
+
+	props := map[string]string{}
+	var walk func(prefix string, list []xfconfProperty)
+	walk = func(prefix string, list []xfconfProperty) {
+		for _, p := range list {
+			path := prefix + "/" + p.Name
+			if p.Value != "" {
+				props[path] = p.Value
+			}
+			walk(path, p.Properties)
+		}
+	}
+	walk("", channel.Properties)
+	return props, nil
+}
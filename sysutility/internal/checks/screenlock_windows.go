@@ -0,0 +1,9 @@
+//go:build windows
+// +build windows
+
+package checks
+
+func checkScreenLock(policy Policy) *ScreenLockStatus {
+	// Not implemented yet
+	return nil
+}
@@ -0,0 +1,139 @@
+//go:build linux
+// +build linux
+
+package checks
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+const logindSessionsDir = "/run/systemd/sessions"
+
+// loginSession is a session tracked by systemd-logind.
+type loginSession struct {
+	ID      string
+	UID     int
+	User    string
+	Home    string
+	Type    string
+	Desktop string
+	State   string
+}
+
+func (s loginSession) graphical() bool {
+	return s.Type == "x11" || s.Type == "wayland" || s.Type == "mir"
+}
+
+// listSessions reads the session state files logind keeps under /run.
+func listSessions() []loginSession {
+	entries, err := os.ReadDir(logindSessionsDir)
+	if err != nil {
+		return nil
+	}
+
+	var sessions []loginSession
+	for _, entry := range entries {
+		// logind also keeps *.ref FIFOs next to the state files
+		if entry.IsDir() || strings.Contains(entry.Name(), ".") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(logindSessionsDir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		session := parseSessionFile(data)
+		session.ID = entry.Name()
+		if session.State == "closing" {
+			continue
+		}
+		if u, err := user.LookupId(strconv.Itoa(session.UID)); err == nil {
+			session.Home = u.HomeDir
+			if session.User == "" {
+				session.User = u.Username
+			}
+		}
+		sessions = append(sessions, session)
+	}
+
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].ID < sessions[j].ID })
+	return sessions
+}
+
+// parseSessionFile parses a logind session file (KEY=value lines).
+func parseSessionFile(data []byte) loginSession {
+	var session loginSession
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "UID":
+			session.UID, _ = strconv.Atoi(value)
+		case "USER":
+			session.User = value
+		case "TYPE":
+			session.Type = value
+		case "DESKTOP":
+			session.Desktop = value
+		case "STATE":
+			session.State = value
+		}
+	}
+	return session
+}
+
+// graphicalUsers returns one session per user with a graphical login.
+func graphicalUsers() []loginSession {
+	var users []loginSession
+	seen := map[int]bool{}
+	for _, session := range listSessions() {
+		if session.graphical() && !seen[session.UID] {
+			seen[session.UID] = true
+			users = append(users, session)
+		}
+	}
+	return users
+}
+
+// userCommand builds a command that runs with the given user's identity and
+// session environment, so per-user tools like gsettings read that user's
+// settings. It returns nil when the agent cannot switch to the user.
+func userCommand(uid int, username, home string, name string, args ...string) *exec.Cmd {
+	runtimeDir := "/run/user/" + strconv.Itoa(uid)
+	env := []string{
+		"HOME=" + home,
+		"XDG_RUNTIME_DIR=" + runtimeDir,
+		"DBUS_SESSION_BUS_ADDRESS=unix:path=" + runtimeDir + "/bus",
+	}
+
+	switch os.Geteuid() {
+	case uid:
+		cmd := exec.Command(name, args...)
+		cmd.Env = append(os.Environ(), env...)
+		return cmd
+	case 0:
+		full := append([]string{"-u", username, "--", "env"}, env...)
+		full = append(full, name)
+		return exec.Command("runuser", append(full, args...)...)
+	}
+	return nil
+}
+
+// statUID returns the owner of a file, or -1 if unknown.
+func statUID(info os.FileInfo) int {
+	if st, ok := info.Sys().(*syscall.Stat_t); ok {
+		return int(st.Uid)
+	}
+	return -1
+}
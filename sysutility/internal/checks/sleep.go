@@ -0,0 +1,10 @@
+package checks
+
+// UserSleepSetting is one user's desktop idle-sleep configuration.
+type UserSleepSetting struct {
+	User string `json:"user"`
+	// Source is the desktop whose settings were read; empty if none found.
+	Source         string `json:"source,omitempty"`
+	TimeoutMinutes int    `json:"timeout_minutes"`
+	SleepOK        bool   `json:"sleep_ok"`
+}
@@ -10,7 +10,7 @@ import (
 	"strings"
 )
 
-func checkSleepSettings() bool {
+func checkSleepSettings() (bool, []UserSleepSetting) {
 	// Method 1: Check display sleep settings
 	displaySleepCmd := exec.Command("pmset", "-g")
 	out, err := displaySleepCmd.Output()
@@ -24,7 +24,7 @@ func checkSleepSettings() bool {
 		if len(displayMatches) > 1 {
 			if val, err := strconv.ParseInt(displayMatches[1], 10, 64); err == nil {
 				if val <= 10 { // 10 minutes or less
-					return true
+					return true, nil
 				}
 			}
 		}
@@ -36,7 +36,7 @@ func checkSleepSettings() bool {
 		if len(sleepMatches) > 1 {
 			if val, err := strconv.ParseInt(sleepMatches[1], 10, 64); err == nil {
 				if val <= 10 { // 10 minutes or less
-					return true
+					return true, nil
 				}
 			}
 		}
@@ -48,7 +48,7 @@ func checkSleepSettings() bool {
 	if err == nil {
 		timeStr := strings.TrimSpace(string(out))
 		if val, err := strconv.ParseInt(timeStr, 10, 64); err == nil {
-			return val <= 600 // Value is in seconds, so 600 = 10 minutes
+			return val <= 600, nil // Value is in seconds, so 600 = 10 minutes
 		}
 	}
 
@@ -64,7 +64,7 @@ func checkSleepSettings() bool {
 
 		if len(displayMatches) > 1 {
 			if val, err := strconv.ParseInt(displayMatches[1], 10, 64); err == nil {
-				return val <= 10 // Value is in minutes
+				return val <= 10, nil // Value is in minutes
 			}
 		}
 
@@ -73,10 +73,10 @@ func checkSleepSettings() bool {
 
 		if len(systemMatches) > 1 {
 			if val, err := strconv.ParseInt(systemMatches[1], 10, 64); err == nil {
-				return val <= 10 // Value is in minutes
+				return val <= 10, nil // Value is in minutes
 			}
 		}
 	}
 
-	return false // Default to false if no sleep settings found
+	return false, nil // Default to false if no sleep settings found
 }
@@ -4,123 +4,135 @@
 package checks
 
 import (
-	"os/exec"
+	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
-	"bufio"
-	"bytes"
-	"io/ioutil"
-	"path/filepath"
 )
 
-func checkSleepSettings() bool {
-	// Method 1: Check systemd settings (for modern Linux distros)
-	out, err := exec.Command("systemctl", "show", "-p", "IdleAction", "sleep.target").Output()
-	if err == nil {
-		output := string(out)
-		if strings.Contains(output, "IdleAction=") {
-			// Found systemd idle action setting
-			parts := strings.SplitN(output, "=", 2)
-			if len(parts) == 2 && parts[1] != "ignore" && parts[1] != "" {
-				// There is a sleep action configured
+func checkSleepSettings() (bool, []UserSleepSetting) {
+	// Method 1: Check the systemd-logind idle action (for modern Linux distros)
+	if logind := checkLogind(); logind.sleeps() && logind.IdleActionSeconds > 0 && logind.IdleActionSeconds <= 600 {
+		// logind enforces this for every session
+		return true, evaluateUserSleepSettings()
+	}
 
-				// Now check the timeout
-				timeOut, err := exec.Command("systemctl", "show", "-p", "IdleActionSec", "sleep.target").Output()
-				if err == nil {
-					timeStr := string(timeOut)
-					parts := strings.SplitN(timeStr, "=", 2)
-					if len(parts) == 2 {
-						// Try to parse the timeout value (might be in format like "30min")
-						timeStr = strings.TrimSpace(parts[1])
-						timeStr = strings.ReplaceAll(timeStr, "min", "")
-						timeStr = strings.ReplaceAll(timeStr, "s", "")
-						
-						if secs, err := strconv.ParseInt(timeStr, 10, 64); err == nil {
-							return secs <= 600 // 10 minutes or less
-						}
-					}
-				}
-			}
+	// Method 2: Evaluate each user's desktop power settings
+	users := evaluateUserSleepSettings()
+	configured := 0
+	for _, u := range users {
+		if u.Source == "" {
+			continue
 		}
-	}
-	
-	// Method 2: Check for gsettings in GNOME
-	out, err = exec.Command("gsettings", "get", "org.gnome.settings-daemon.plugins.power", "sleep-inactive-ac-timeout").Output()
-	if err == nil {
-		timeStr := strings.TrimSpace(string(out))
-		if timeVal, err := strconv.ParseInt(timeStr, 10, 64); err == nil {
-			return timeVal <= 600 // 10 minutes or less
+		configured++
+		if !u.SleepOK {
+			return false, users
 		}
 	}
-	
-	// Method 3: Check xfce power manager settings
-	xfceConfig := filepath.Join(getHomeDir(), ".config", "xfce4", "xfconf", "xfce-perchannel-xml", "xfce4-power-manager.xml")
-	if data, err := ioutil.ReadFile(xfceConfig); err == nil {
-		content := string(data)
-		if strings.Contains(content, "inactivity-sleep-mode-ac") && strings.Contains(content, "inactivity-on-ac") {
-			// Extract the timeout value
-			scanner := bufio.NewScanner(bytes.NewReader(data))
-			sleepEnabled := false
-			var timeoutVal int64 = 0
-			
-			for scanner.Scan() {
-				line := scanner.Text()
-				if strings.Contains(line, "inactivity-sleep-mode-ac") && strings.Contains(line, "value=\"1\"") {
-					sleepEnabled = true
-				}
-				if strings.Contains(line, "inactivity-on-ac") {
-					parts := strings.Split(line, "value=\"")
-					if len(parts) > 1 {
-						valParts := strings.Split(parts[1], "\"")
-						if len(valParts) > 0 {
-							if val, err := strconv.ParseInt(valParts[0], 10, 64); err == nil {
-								timeoutVal = val
-							}
-						}
-					}
-				}
-			}
-			
-			if sleepEnabled && timeoutVal > 0 {
-				return timeoutVal <= 10 // XFCE uses minutes, so 10 = 10 minutes
+
+	return configured > 0, users // Default to false if no sleep settings found
+}
+
+// evaluateUserSleepSettings checks the desktop power settings of every
+// human user, not just the account the agent runs as.
+func evaluateUserSleepSettings() []UserSleepSetting {
+	desktops := map[int]string{}
+	for _, session := range graphicalUsers() {
+		desktops[session.UID] = session.Desktop
+	}
+
+	var results []UserSleepSetting
+	for _, u := range humanUsers() {
+		setting := UserSleepSetting{User: u.Name}
+		type probe struct {
+			source string
+			read   func(localUser) (int, bool)
+		}
+		// gsettings answers with schema defaults for anyone, so GNOME is
+		// only trusted for GNOME sessions or users with stored settings
+		var probes []probe
+		if isGNOMEDesktop(desktops[u.UID]) {
+			probes = append(probes, probe{"gnome", gnomeSleepTimeout})
+		}
+		probes = append(probes, probe{"xfce", xfceSleepTimeout}, probe{"kde", kdeSleepTimeout})
+		if !isGNOMEDesktop(desktops[u.UID]) && fileExists(filepath.Join(u.Home, ".config", "dconf", "user")) {
+			probes = append(probes, probe{"gnome", gnomeSleepTimeout})
+		}
+		for _, p := range probes {
+			if minutes, ok := p.read(u); ok {
+				setting.Source = p.source
+				setting.TimeoutMinutes = minutes
+				setting.SleepOK = minutes > 0 && minutes <= 10 // 10 minutes or less
+				break
 			}
 		}
+		results = append(results, setting)
 	}
-	
-	// Method 4: Check KDE settings
-	kdeConfig := filepath.Join(getHomeDir(), ".config", "powermanagementprofilesrc")
-	if data, err := ioutil.ReadFile(kdeConfig); err == nil {
-		scanner := bufio.NewScanner(bytes.NewReader(data))
-		inACSection := false
-		
-		for scanner.Scan() {
-			line := scanner.Text()
-			if strings.Contains(line, "[AC]") {
-				inACSection = true
-				continue
-			}
-			if inACSection && strings.HasPrefix(line, "[") {
-				// Left the AC section
-				inACSection = false
-			}
-			if inACSection && strings.HasPrefix(line, "SuspendSession=") {
-				parts := strings.SplitN(line, "=", 2)
-				if len(parts) == 2 {
-					if timeVal, err := strconv.ParseInt(parts[1], 10, 64); err == nil {
-						return timeVal <= 10 // KDE also uses minutes
-					}
-				}
-			}
+	return results
+}
+
+// isGNOMEDesktop reports whether an XDG desktop name is GNOME or one of
+// the GNOME-based Ubuntu sessions.
+func isGNOMEDesktop(desktop string) bool {
+	desktop = strings.ToLower(desktop)
+	return strings.Contains(desktop, "gnome") || strings.Contains(desktop, "ubuntu") || strings.Contains(desktop, "unity")
+}
+
+// gnomeSleepTimeout reads the GNOME AC sleep timeout for the user. A value
+// of 0 or an action of 'nothing' means the machine never sleeps.
+func gnomeSleepTimeout(u localUser) (int, bool) {
+	cmd := userCommand(u.UID, u.Name, u.Home, "gsettings", "get", "org.gnome.settings-daemon.plugins.power", "sleep-inactive-ac-timeout")
+	if cmd == nil {
+		return 0, false
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		return 0, false
+	}
+	secs := parseGVariantUint(strings.TrimSpace(string(out)))
+
+	if cmd := userCommand(u.UID, u.Name, u.Home, "gsettings", "get", "org.gnome.settings-daemon.plugins.power", "sleep-inactive-ac-type"); cmd != nil {
+		if out, err := cmd.Output(); err == nil && strings.Trim(strings.TrimSpace(string(out)), "'") == "nothing" {
+			secs = 0
 		}
 	}
+	return (secs + 59) / 60, true
+}
 
-	return false // Default to false if no sleep settings found
+// xfceSleepTimeout reads xfce4-power-manager, which stores minutes.
+func xfceSleepTimeout(u localUser) (int, bool) {
+	props, err := readXfconfChannel(u.Home, "xfce4-power-manager")
+	if err != nil {
+		return 0, false
+	}
+	if props["/xfce4-power-manager/inactivity-sleep-mode-on-ac"] != "1" && props["/xfce4-power-manager/inactivity-sleep-mode-ac"] != "1" {
+		return 0, true
+	}
+	minutes, _ := strconv.Atoi(props["/xfce4-power-manager/inactivity-on-ac"])
+	return minutes, true
 }
 
-func getHomeDir() string {
-	out, err := exec.Command("sh", "-c", "echo $HOME").Output()
-	if err == nil {
-		return strings.TrimSpace(string(out))
+// kdeSleepTimeout reads Plasma 6's powerdevilrc or Plasma 5's
+// powermanagementprofilesrc for the AC profile.
+func kdeSleepTimeout(u localUser) (int, bool) {
+	if data, err := os.ReadFile(filepath.Join(u.Home, ".config", "powerdevilrc")); err == nil {
+		section := parseINI(data)["AC][SuspendAndShutdown"]
+		if section["AutoSuspendAction"] == "0" {
+			return 0, true
+		}
+		if secs, err := strconv.Atoi(section["AutoSuspendIdleTimeoutSec"]); err == nil {
+			return (secs + 59) / 60, true
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(u.Home, ".config", "powermanagementprofilesrc"))
+	if err != nil {
+		return 0, false
+	}
+	// idleTime is in milliseconds
+	ms, err := strconv.Atoi(parseINI(data)["AC][SuspendSession"]["idleTime"])
+	if err != nil {
+		return 0, true
 	}
-	return "/home/" // fallback
+	return (ms/1000 + 59) / 60, true
 }
@@ -9,30 +9,30 @@ import (
     "strings"
 )
 
-func checkSleepSettings() bool {
+func checkSleepSettings() (bool, []UserSleepSetting) {
     cmd := `powercfg -query SCHEME_CURRENT SUB_SLEEP STANDBYIDLE`
     out, err := exec.Command("powershell", "-Command", cmd).Output()
     if err != nil {
-        return false
+        return false, nil
     }
 
     str := string(out)
     idx := strings.Index(str, "Current AC Power Setting Index")
     if idx == -1 {
-        return false
+        return false, nil
     }
 
     line := str[idx:]
     fields := strings.Fields(line)
     if len(fields) < 7 {
-        return false
+        return false, nil
     }
 
     valHex := fields[6]
     val, err := strconv.ParseInt(valHex, 16, 64)
     if err != nil {
-        return false
+        return false, nil
     }
 
-    return val <= 600 // seconds
+    return val <= 600, nil // seconds
 }
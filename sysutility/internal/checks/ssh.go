@@ -0,0 +1,288 @@
+package checks
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// SSHStatus is the effective sshd configuration relevant to hardening.
+type SSHStatus struct {
+	Installed              bool          `json:"installed"`
+	PermitRootLogin        string        `json:"permit_root_login,omitempty"`
+	PasswordAuthentication string        `json:"password_authentication,omitempty"`
+	Ciphers                []string      `json:"ciphers,omitempty"`
+	MACs                   []string      `json:"macs,omitempty"`
+	KexAlgorithms          []string      `json:"kex_algorithms,omitempty"`
+	WeakAlgorithms         []string      `json:"weak_algorithms,omitempty"`
+	HostKeys               []SSHHostKey  `json:"host_keys,omitempty"`
+	MatchOverrides         []SSHOverride `json:"match_overrides,omitempty"`
+	Hardened               bool          `json:"hardened"`
+}
+
+type SSHHostKey struct {
+	Path string `json:"path"`
+	Type string `json:"type"`
+	Bits int    `json:"bits,omitempty"`
+	Weak bool   `json:"weak"`
+}
+
+// SSHOverride is a setting changed inside a Match block, e.g. password
+// logins re-enabled for one group.
+type SSHOverride struct {
+	Match   string `json:"match"`
+	Keyword string `json:"keyword"`
+	Value   string `json:"value"`
+}
+
+var weakSSHAlgorithms = map[string]bool{
+	"3des-cbc":                           true,
+	"aes128-cbc":                         true,
+	"aes192-cbc":                         true,
+	"aes256-cbc":                         true,
+	"blowfish-cbc":                       true,
+	"cast128-cbc":                        true,
+	"arcfour":                            true,
+	"arcfour128":                         true,
+	"arcfour256":                         true,
+	"hmac-md5":                           true,
+	"hmac-md5-96":                        true,
+	"hmac-md5-etm@openssh.com":           true,
+	"hmac-md5-96-etm@openssh.com":        true,
+	"hmac-sha1":                          true,
+	"hmac-sha1-96":                       true,
+	"hmac-sha1-etm@openssh.com":          true,
+	"hmac-sha1-96-etm@openssh.com":       true,
+	"hmac-ripemd160":                     true,
+	"umac-64@openssh.com":                true,
+	"umac-64-etm@openssh.com":            true,
+	"diffie-hellman-group1-sha1":         true,
+	"diffie-hellman-group14-sha1":        true,
+	"diffie-hellman-group-exchange-sha1": true,
+}
+
+// sshdKeywords are the keywords the check cares about, lower-cased.
+var sshdKeywords = map[string]bool{
+	"permitrootlogin":        true,
+	"passwordauthentication": true,
+	"ciphers":                true,
+	"macs":                   true,
+	"kexalgorithms":          true,
+	"hostkey":                true,
+}
+
+type sshdConfig struct {
+	global    map[string]string
+	hostKeys  []string
+	overrides []SSHOverride
+}
+
+// evaluateSSHConfig reads sshd_config under dir and judges the result.
+func evaluateSSHConfig(dir string) *SSHStatus {
+	mainConfig := filepath.Join(dir, "sshd_config")
+	if _, err := os.Stat(mainConfig); err != nil {
+		return &SSHStatus{}
+	}
+
+	cfg := &sshdConfig{global: map[string]string{}}
+	parseSSHDConfigFile(cfg, mainConfig, dir, "", 0)
+
+	status := &SSHStatus{
+		Installed:              true,
+		PermitRootLogin:        valueOr(cfg.global["permitrootlogin"], "prohibit-password"),
+		PasswordAuthentication: valueOr(cfg.global["passwordauthentication"], "yes"),
+		Ciphers:                splitSSHList(cfg.global["ciphers"]),
+		MACs:                   splitSSHList(cfg.global["macs"]),
+		KexAlgorithms:          splitSSHList(cfg.global["kexalgorithms"]),
+		MatchOverrides:         cfg.overrides,
+	}
+
+	for _, list := range [][]string{status.Ciphers, status.MACs, status.KexAlgorithms} {
+		for _, alg := range list {
+			if weakSSHAlgorithms[alg] {
+				status.WeakAlgorithms = append(status.WeakAlgorithms, alg)
+			}
+		}
+	}
+
+	hostKeys := cfg.hostKeys
+	if len(hostKeys) == 0 {
+		for _, t := range []string{"rsa", "ecdsa", "ed25519"} {
+			path := filepath.Join(dir, "ssh_host_"+t+"_key")
+			if _, err := os.Stat(path + ".pub"); err == nil {
+				hostKeys = append(hostKeys, path)
+			}
+		}
+	}
+	for _, path := range hostKeys {
+		if data, err := os.ReadFile(path + ".pub"); err == nil {
+			key := parseSSHPublicKey(data)
+			key.Path = path
+			status.HostKeys = append(status.HostKeys, key)
+		}
+	}
+
+	status.Hardened = status.PermitRootLogin != "yes" &&
+		status.PasswordAuthentication == "no" &&
+		len(status.WeakAlgorithms) == 0
+	for _, key := range status.HostKeys {
+		if key.Weak {
+			status.Hardened = false
+		}
+	}
+	for _, o := range status.MatchOverrides {
+		if (o.Keyword == "permitrootlogin" && o.Value == "yes") || (o.Keyword == "passwordauthentication" && o.Value == "yes") {
+			status.Hardened = false
+		}
+	}
+
+	return status
+}
+
+// parseSSHDConfigFile applies one config file to cfg. sshd keeps the first
+// value it sees for a keyword, so later occurrences are ignored; Include
+// directives are expanded in place.
+func parseSSHDConfigFile(cfg *sshdConfig, path, dir, match string, depth int) {
+	if depth > 16 {
+		return
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+
+	matchSeen := map[string]bool{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		keyword, value := splitSSHDLine(line)
+		keyword = strings.ToLower(keyword)
+
+		switch keyword {
+		case "include":
+			for _, pattern := range strings.Fields(value) {
+				if !filepath.IsAbs(pattern) {
+					pattern = filepath.Join(dir, pattern)
+				}
+				files, _ := filepath.Glob(pattern)
+				for _, file := range files {
+					parseSSHDConfigFile(cfg, file, dir, match, depth+1)
+				}
+			}
+			continue
+		case "match":
+			match = value
+			if strings.EqualFold(value, "all") {
+				match = ""
+			}
+			matchSeen = map[string]bool{}
+			continue
+		}
+
+		if !sshdKeywords[keyword] {
+			continue
+		}
+		if match != "" {
+			if !matchSeen[keyword] {
+				matchSeen[keyword] = true
+				cfg.overrides = append(cfg.overrides, SSHOverride{Match: match, Keyword: keyword, Value: strings.ToLower(value)})
+			}
+			continue
+		}
+		if keyword == "hostkey" {
+			cfg.hostKeys = append(cfg.hostKeys, value)
+			continue
+		}
+		if _, ok := cfg.global[keyword]; !ok {
+			cfg.global[keyword] = value
+		}
+	}
+}
+
+// splitSSHDLine splits "Keyword value" or "Keyword=value".
+func splitSSHDLine(line string) (string, string) {
+	idx := strings.IndexAny(line, " \t=")
+	if idx < 0 {
+		return line, ""
+	}
+	keyword := line[:idx]
+	value := strings.TrimLeft(line[idx:], " \t")
+	value = strings.TrimPrefix(value, "=")
+	return keyword, strings.Trim(strings.TrimSpace(value), "\"")
+}
+
+// splitSSHList splits an algorithm list. Entries prefixed with "-" remove
+// algorithms from the defaults and are dropped; "+" and "^" only change
+// where the listed algorithms go, so the names are kept.
+func splitSSHList(value string) []string {
+	if value == "" || strings.HasPrefix(value, "-") {
+		return nil
+	}
+	value = strings.TrimLeft(value, "+^")
+	var algs []string
+	for _, alg := range strings.Split(value, ",") {
+		if alg = strings.TrimSpace(alg); alg != "" {
+			algs = append(algs, strings.ToLower(alg))
+		}
+	}
+	return algs
+}
+
+// parseSSHPublicKey reads the type and size of an OpenSSH public key line.
+// DSA keys and RSA keys below 2048 bits are weak.
+func parseSSHPublicKey(data []byte) SSHHostKey {
+	fields := strings.Fields(string(data))
+	if len(fields) < 2 {
+		return SSHHostKey{Type: "unknown"}
+	}
+	key := SSHHostKey{Type: fields[0]}
+
+	switch key.Type {
+	case "ssh-dss":
+		key.Bits = 1024
+		key.Weak = true
+	case "ssh-rsa":
+		blob, err := base64.StdEncoding.DecodeString(fields[1])
+		if err != nil {
+			break
+		}
+		// string "ssh-rsa", mpint e, mpint n
+		var parts [][]byte
+		for len(blob) >= 4 && len(parts) < 3 {
+			n := binary.BigEndian.Uint32(blob)
+			if uint64(len(blob)-4) < uint64(n) {
+				break
+			}
+			parts = append(parts, blob[4:4+n])
+			blob = blob[4+n:]
+		}
+		if len(parts) == 3 {
+			key.Bits = new(big.Int).SetBytes(parts[2]).BitLen()
+		}
+		key.Weak = key.Bits < 2048
+	case "ecdsa-sha2-nistp256":
+		key.Bits = 256
+	case "ecdsa-sha2-nistp384":
+		key.Bits = 384
+	case "ecdsa-sha2-nistp521":
+		key.Bits = 521
+	case "ssh-ed25519":
+		key.Bits = 256
+	}
+	return key
+}
+
+func valueOr(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return strings.ToLower(value)
+}
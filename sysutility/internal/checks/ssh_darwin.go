@@ -0,0 +1,8 @@
+//go:build darwin
+// +build darwin
+
+package checks
+
+func checkSSH() *SSHStatus {
+	return evaluateSSHConfig("/etc/ssh")
+}
@@ -0,0 +1,8 @@
+//go:build linux
+// +build linux
+
+package checks
+
+func checkSSH() *SSHStatus {
+	return evaluateSSHConfig("/etc/ssh")
+}
@@ -0,0 +1,14 @@
+//go:build windows
+// +build windows
+
+package checks
+
+import (
+	"os"
+	"path/filepath"
+)
+
+func checkSSH() *SSHStatus {
+	// The Windows OpenSSH server keeps its config under ProgramData
+	return evaluateSSHConfig(filepath.Join(os.Getenv("ProgramData"), "ssh"))
+}
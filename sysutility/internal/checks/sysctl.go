@@ -0,0 +1,99 @@
+package checks
+
+import (
+	"strconv"
+	"strings"
+)
+
+// defaultSysctlBaseline is the hardening baseline used unless the policy
+// overrides a key. Values are exact, or ">=N" for a minimum.
+var defaultSysctlBaseline = map[string]string{
+	"kernel.kptr_restrict":                  ">=1",
+	"kernel.dmesg_restrict":                 "1",
+	"kernel.randomize_va_space":             "2",
+	"kernel.yama.ptrace_scope":              ">=1",
+	"kernel.unprivileged_bpf_disabled":      ">=1",
+	"net.ipv4.ip_forward":                   "0",
+	"net.ipv4.conf.all.rp_filter":           ">=1",
+	"net.ipv4.conf.default.rp_filter":       ">=1",
+	"net.ipv4.conf.all.accept_redirects":    "0",
+	"net.ipv4.conf.all.send_redirects":      "0",
+	"net.ipv4.conf.all.accept_source_route": "0",
+	"net.ipv4.tcp_syncookies":               "1",
+	"fs.protected_hardlinks":                "1",
+	"fs.protected_symlinks":                 "1",
+	"fs.protected_fifos":                    ">=1",
+	"fs.protected_regular":                  ">=1",
+	"fs.suid_dumpable":                      "0",
+}
+
+// SysctlStatus compares kernel parameters with the baseline and with the
+// values configured for the next boot.
+type SysctlStatus struct {
+	Checked    int               `json:"checked"`
+	Violations []SysctlViolation `json:"violations,omitempty"`
+	// BootMismatches are parameters whose runtime value differs from what
+	// sysctl.d would set, i.e. changed by hand or by a tool since boot.
+	BootMismatches []SysctlMismatch `json:"boot_mismatches,omitempty"`
+}
+
+type SysctlViolation struct {
+	Key      string `json:"key"`
+	Value    string `json:"value"`
+	Expected string `json:"expected"`
+}
+
+type SysctlMismatch struct {
+	Key     string `json:"key"`
+	Runtime string `json:"runtime"`
+	Boot    string `json:"boot"`
+	Source  string `json:"source"`
+}
+
+// sysctlBaseline merges the policy's entries over the defaults. An empty
+// expected value removes a key from the baseline.
+func sysctlBaseline(policy Policy) map[string]string {
+	baseline := map[string]string{}
+	for key, expected := range defaultSysctlBaseline {
+		baseline[key] = expected
+	}
+	for key, expected := range policy.SysctlBaseline {
+		key = normalizeSysctlKey(key)
+		if expected == "" {
+			delete(baseline, key)
+		} else {
+			baseline[key] = expected
+		}
+	}
+	return baseline
+}
+
+// sysctlSatisfies checks a value against "N" or ">=N". Multi-value
+// parameters compare whitespace-normalized.
+func sysctlSatisfies(value, expected string) bool {
+	if min, ok := strings.CutPrefix(expected, ">="); ok {
+		v, err1 := strconv.Atoi(value)
+		m, err2 := strconv.Atoi(strings.TrimSpace(min))
+		return err1 == nil && err2 == nil && v >= m
+	}
+	return strings.Join(strings.Fields(value), " ") == strings.Join(strings.Fields(expected), " ")
+}
+
+// normalizeSysctlKey converts the slash form "net/ipv4/conf/eth0.100/rp_filter"
+// to the dotted form "net.ipv4.conf.eth0/100.rp_filter". Like sysctl(8),
+// the first separator decides which form a key uses.
+func normalizeSysctlKey(key string) string {
+	key = strings.TrimSpace(key)
+	if i := strings.IndexAny(key, "./"); i < 0 || key[i] == '.' {
+		return key
+	}
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '/':
+			return '.'
+		case '.':
+			return '/'
+		}
+		return r
+	}, key)
+}
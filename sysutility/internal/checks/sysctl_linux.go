@@ -0,0 +1,166 @@
+//go:build linux
+// +build linux
+
+package checks
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Directories systemd-sysctl reads, highest precedence first.
+var sysctlConfigDirs = []string{
+	"/etc",
+	"/run",
+	"/usr/local/lib",
+	"/usr/lib",
+	"/lib",
+}
+
+func checkSysctl(policy Policy) *SysctlStatus {
+	status := &SysctlStatus{}
+
+	baseline := sysctlBaseline(policy)
+	keys := make([]string, 0, len(baseline))
+	for key := range baseline {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value, ok := readSysctl(key)
+		// Parameters the kernel lacks (e.g. Yama not built in) are skipped
+		if !ok {
+			continue
+		}
+		status.Checked++
+		if !sysctlSatisfies(value, baseline[key]) {
+			status.Violations = append(status.Violations, SysctlViolation{Key: key, Value: value, Expected: baseline[key]})
+		}
+	}
+
+	boot := bootSysctlSettings()
+	bootKeys := make([]string, 0, len(boot))
+	for key := range boot {
+		bootKeys = append(bootKeys, key)
+	}
+	sort.Strings(bootKeys)
+	for _, key := range bootKeys {
+		value, ok := readSysctl(key)
+		if ok && !sysctlSatisfies(value, boot[key].value) {
+			status.BootMismatches = append(status.BootMismatches, SysctlMismatch{
+				Key:     key,
+				Runtime: value,
+				Boot:    boot[key].value,
+				Source:  boot[key].source,
+			})
+		}
+	}
+	return status
+}
+
+func readSysctl(key string) (string, bool) {
+	data, err := os.ReadFile(sysctlPath(key))
+	if err != nil {
+		return "", false
+	}
+	return strings.TrimSpace(string(data)), true
+}
+
+// sysctlPath maps a dotted key to /proc/sys. Slashes in a dotted key stand
+// for dots in a path component, as in "net.ipv4.conf.eth0/100.rp_filter".
+func sysctlPath(key string) string {
+	parts := strings.Split(key, ".")
+	for i := range parts {
+		parts[i] = strings.ReplaceAll(parts[i], "/", ".")
+	}
+	return filepath.Join(append([]string{"/proc/sys"}, parts...)...)
+}
+
+type sysctlSetting struct {
+	value  string
+	source string
+	glob   bool
+}
+
+// bootSysctlSettings applies sysctl.d files in systemd-sysctl order, then
+// /etc/sysctl.conf as `sysctl --system` does. Glob keys are expanded
+// against /proc/sys and never override an explicitly named key or one
+// excluded with a "-key" line in any file.
+func bootSysctlSettings() map[string]sysctlSetting {
+	files := configDropIns(sysctlConfigDirs, "sysctl.d/*.conf")
+	files = append(files, "/etc/sysctl.conf")
+
+	type parsedFile struct {
+		path     string
+		settings map[string]string
+	}
+	var parsed []parsedFile
+	excluded := map[string]bool{}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		settings, exclusions := parseSysctlConf(data)
+		for _, key := range exclusions {
+			excluded[key] = true
+		}
+		parsed = append(parsed, parsedFile{path: file, settings: settings})
+	}
+
+	settings := map[string]sysctlSetting{}
+	for _, file := range parsed {
+		for key, value := range file.settings {
+			if !strings.ContainsAny(key, "*?[") {
+				settings[key] = sysctlSetting{value: value, source: file.path}
+				continue
+			}
+			matches, _ := filepath.Glob(sysctlPath(key))
+			for _, match := range matches {
+				rel, _ := filepath.Rel("/proc/sys", match)
+				parts := strings.Split(rel, string(filepath.Separator))
+				for i := range parts {
+					parts[i] = strings.ReplaceAll(parts[i], ".", "/")
+				}
+				name := strings.Join(parts, ".")
+				if excluded[name] {
+					continue
+				}
+				if existing, ok := settings[name]; !ok || existing.glob {
+					settings[name] = sysctlSetting{value: value, source: file.path, glob: true}
+				}
+			}
+		}
+	}
+	return settings
+}
+
+// parseSysctlConf parses "key = value" lines. A leading "-" on an
+// assignment only suppresses errors and is dropped; "-key" on its own
+// excludes the key from glob assignments, as systemd's stock
+// 50-default.conf does with "-net.ipv4.conf.all.rp_filter".
+func parseSysctlConf(data []byte) (map[string]string, []string) {
+	settings := map[string]string{}
+	var excluded []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			if strings.HasPrefix(line, "-") {
+				excluded = append(excluded, normalizeSysctlKey(strings.TrimSpace(line[1:])))
+			}
+			continue
+		}
+		key = normalizeSysctlKey(strings.TrimPrefix(strings.TrimSpace(key), "-"))
+		settings[key] = strings.TrimSpace(value)
+	}
+	return settings, excluded
+}
@@ -8,13 +8,43 @@ type SystemReport struct {
 	DiskEncrypted        bool   `json:"disk_encrypted"`
 	DiskEncryptionMethod string `json:"disk_encryption_method,omitempty"`
 
-	OSUpToDate     bool   `json:"os_up_to_date"`
-	CurrentVersion string `json:"current_os_version,omitempty"`
-	LatestVersion  string `json:"latest_os_version,omitempty"`
+	OSUpToDate     bool              `json:"os_up_to_date"`
+	CurrentVersion string            `json:"current_os_version,omitempty"`
+	LatestVersion  string            `json:"latest_os_version,omitempty"`
+	OSUpdates      *UpdateStatus     `json:"os_updates,omitempty"`
+	EOL            *EOLStatus        `json:"eol,omitempty"`
+	Reboot         *RebootStatus     `json:"reboot,omitempty"`
+	AutoUpdates    *AutoUpdateStatus `json:"auto_updates,omitempty"`
 
-	AntivirusExists bool   `json:"antivirus_exists"`
-	AntivirusActive bool   `json:"antivirus_active"`
-	AntivirusName   string `json:"antivirus_name,omitempty"`
+	AntivirusExists   bool               `json:"antivirus_exists"`
+	AntivirusActive   bool               `json:"antivirus_active"`
+	AntivirusName     string             `json:"antivirus_name,omitempty"`
+	AntivirusProducts []AntivirusProduct `json:"antivirus_products,omitempty"`
+	ClamAV            *ClamAVStatus      `json:"clamav,omitempty"`
 
-	SleepOK bool `json:"sleep_ok"`
+	SleepOK    bool               `json:"sleep_ok"`
+	SleepUsers []UserSleepSetting `json:"sleep_users,omitempty"`
+	Logind     *LogindStatus      `json:"logind,omitempty"`
+
+	LoggingOK bool           `json:"logging_ok"`
+	Logging   *LoggingStatus `json:"logging,omitempty"`
+
+	TimeSync *TimeSyncStatus `json:"time_sync,omitempty"`
+
+	TPM      *TPMStatus      `json:"tpm,omitempty"`
+	Firewall *FirewallStatus `json:"firewall,omitempty"`
+
+	ListeningPorts []ListeningSocket `json:"listening_ports,omitempty"`
+	MAC            *MACStatus        `json:"mac,omitempty"`
+	Sysctl         *SysctlStatus     `json:"sysctl,omitempty"`
+	Mounts         *MountStatus      `json:"mounts,omitempty"`
+	USB            *USBStatus        `json:"usb,omitempty"`
+
+	SSH            *SSHStatus            `json:"ssh,omitempty"`
+	Accounts       *AccountStatus        `json:"accounts,omitempty"`
+	PasswordPolicy *PasswordPolicyStatus `json:"password_policy,omitempty"`
+
+	ScreenLock *ScreenLockStatus `json:"screen_lock,omitempty"`
+
+	Vulnerabilities *VulnerabilityStatus `json:"vulnerabilities,omitempty"`
 }
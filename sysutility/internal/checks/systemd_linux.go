@@ -0,0 +1,102 @@
+//go:build linux
+// +build linux
+
+package checks
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// systemdUnitProperties runs `systemctl show` for the given properties.
+// Unknown units come back with LoadState=not-found rather than an error.
+func systemdUnitProperties(unit string, properties ...string) map[string]string {
+	args := []string{"show", unit}
+	for _, p := range properties {
+		args = append(args, "-p", p)
+	}
+	cmd := exec.Command("systemctl", args...)
+	// Timestamps are printed in the local zone; UTC parses unambiguously
+	cmd.Env = append(os.Environ(), "TZ=UTC")
+	out, err := cmd.Output()
+	if err != nil {
+		return nil
+	}
+	return parseSystemctlShow(out)
+}
+
+func parseSystemctlShow(data []byte) map[string]string {
+	props := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		if key, value, ok := strings.Cut(scanner.Text(), "="); ok {
+			props[key] = value
+		}
+	}
+	return props
+}
+
+// timerState reports a timer, or nil when the unit does not exist.
+func timerState(unit string) *TimerState {
+	props := systemdUnitProperties(unit, "LoadState", "UnitFileState", "ActiveState", "LastTriggerUSec")
+	if props == nil || props["LoadState"] != "loaded" {
+		return nil
+	}
+	state := &TimerState{
+		Unit:    unit,
+		Enabled: props["UnitFileState"] == "enabled",
+		Active:  props["ActiveState"] == "active",
+	}
+	if t, ok := parseSystemdTimestamp(props["LastTriggerUSec"]); ok {
+		state.LastTrigger = t.UTC().Format(time.RFC3339)
+	}
+	return state
+}
+
+// parseSystemdTimestamp parses systemctl's "Mon 2024-05-06 06:31:02 UTC"
+// format. "n/a" or an empty value means never.
+func parseSystemdTimestamp(s string) (time.Time, bool) {
+	fields := strings.Fields(s)
+	if len(fields) < 4 {
+		return time.Time{}, false
+	}
+	t, err := time.Parse("2006-01-02 15:04:05 MST", strings.Join(fields[1:4], " "))
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// configDropIns returns the files matching pattern (e.g.
+// "logind.conf.d/*.conf") in all dirs, sorted by file name. A file in a
+// higher-priority directory masks one with the same name further down the
+// list, and an empty file or /dev/null symlink masks it entirely.
+func configDropIns(dirs []string, pattern string) []string {
+	byName := map[string]string{}
+	for i := len(dirs) - 1; i >= 0; i-- {
+		matches, _ := filepath.Glob(filepath.Join(dirs[i], pattern))
+		for _, path := range matches {
+			byName[filepath.Base(path)] = path
+		}
+	}
+
+	names := make([]string, 0, len(byName))
+	for name := range byName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var paths []string
+	for _, name := range names {
+		if info, err := os.Stat(byName[name]); err == nil && info.Size() > 0 {
+			paths = append(paths, byName[name])
+		}
+	}
+	return paths
+}
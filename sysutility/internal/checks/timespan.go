@@ -0,0 +1,83 @@
+package checks
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var timespanUnits = map[string]time.Duration{
+	"":        time.Second,
+	"us":      time.Microsecond,
+	"usec":    time.Microsecond,
+	"ms":      time.Millisecond,
+	"msec":    time.Millisecond,
+	"s":       time.Second,
+	"sec":     time.Second,
+	"second":  time.Second,
+	"seconds": time.Second,
+	"m":       time.Minute,
+	"min":     time.Minute,
+	"minute":  time.Minute,
+	"minutes": time.Minute,
+	"h":       time.Hour,
+	"hr":      time.Hour,
+	"hour":    time.Hour,
+	"hours":   time.Hour,
+	"d":       24 * time.Hour,
+	"day":     24 * time.Hour,
+	"days":    24 * time.Hour,
+	"w":       7 * 24 * time.Hour,
+	"week":    7 * 24 * time.Hour,
+	"weeks":   7 * 24 * time.Hour,
+	"M":       2629800 * time.Second,
+	"month":   2629800 * time.Second,
+	"months":  2629800 * time.Second,
+	"y":       31557600 * time.Second,
+	"year":    31557600 * time.Second,
+	"years":   31557600 * time.Second,
+}
+
+// parseTimespan parses a systemd time span such as "30min", "1h 5s" or
+// "90". A bare number is seconds. "infinity" returns -1.
+func parseTimespan(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if value == "infinity" {
+		return -1, nil
+	}
+	if value == "" {
+		return 0, fmt.Errorf("empty time span")
+	}
+
+	var total time.Duration
+	rest := value
+	for rest != "" {
+		rest = strings.TrimLeft(rest, " \t")
+		i := 0
+		for i < len(rest) && (rest[i] >= '0' && rest[i] <= '9' || rest[i] == '.') {
+			i++
+		}
+		if i == 0 {
+			return 0, fmt.Errorf("invalid time span %q", value)
+		}
+		number, err := strconv.ParseFloat(rest[:i], 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid time span %q", value)
+		}
+		rest = strings.TrimLeft(rest[i:], " \t")
+
+		j := 0
+		for j < len(rest) && unicode.IsLetter(rune(rest[j])) {
+			j++
+		}
+		unit, ok := timespanUnits[rest[:j]]
+		if !ok {
+			return 0, fmt.Errorf("unknown time unit %q in %q", rest[:j], value)
+		}
+		total += time.Duration(number * float64(unit))
+		rest = rest[j:]
+	}
+	return total, nil
+}
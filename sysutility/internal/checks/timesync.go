@@ -0,0 +1,137 @@
+package checks
+
+import (
+	"bufio"
+	"bytes"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxServerClockSkew is how far the local clock may drift from the
+// reporting server's before tokens and log timestamps become unreliable.
+// The Date header has one-second resolution, so smaller values are noise.
+const maxServerClockSkew = 5 * time.Second
+
+// TimeSyncStatus reports clock synchronization.
+type TimeSyncStatus struct {
+	// Daemon is "systemd-timesyncd", "chrony", "ntpd", "w32time" or
+	// "timed"; empty when nothing keeps the clock in sync.
+	Daemon       string `json:"daemon,omitempty"`
+	NTPEnabled   bool   `json:"ntp_enabled"`
+	Synchronized bool   `json:"synchronized"`
+	Source       string `json:"source,omitempty"`
+	// OffsetMillis is the daemon's estimate of local minus reference
+	// time; positive means the local clock is ahead.
+	OffsetMillis float64 `json:"offset_ms"`
+
+	// ServerSkewSeconds compares local time with the Date header of the
+	// last report upload, positive when the local clock is ahead.
+	ServerSkewSeconds  *int `json:"server_skew_seconds,omitempty"`
+	ServerSkewExceeded bool `json:"server_skew_exceeded"`
+}
+
+// ApplyServerSkew records the clock skew measured against the reporting
+// server in the report.
+func ApplyServerSkew(report *SystemReport, skew time.Duration) {
+	if report.TimeSync == nil {
+		report.TimeSync = &TimeSyncStatus{}
+	}
+	seconds := int(math.Round(skew.Seconds()))
+	report.TimeSync.ServerSkewSeconds = &seconds
+	report.TimeSync.ServerSkewExceeded = skew > maxServerClockSkew || skew < -maxServerClockSkew
+}
+
+// timeSyncChanged ignores offsets, which differ on every measurement.
+func timeSyncChanged(old, new *TimeSyncStatus) bool {
+	if old == nil || new == nil {
+		return old != new
+	}
+	a, b := *old, *new
+	a.OffsetMillis, b.OffsetMillis = 0, 0
+	a.ServerSkewSeconds, b.ServerSkewSeconds = nil, nil
+	return a != b
+}
+
+// parseChronyTracking reads `chronyc -n tracking`.
+func parseChronyTracking(data []byte) (TimeSyncStatus, bool) {
+	values := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		if key, value, ok := strings.Cut(scanner.Text(), ":"); ok {
+			values[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+	if _, ok := values["Leap status"]; !ok {
+		return TimeSyncStatus{}, false
+	}
+
+	status := TimeSyncStatus{Daemon: "chrony", NTPEnabled: true}
+	status.Synchronized = values["Leap status"] != "Not synchronised"
+	// "A9FEA97B (169.254.169.123)"
+	if _, source, ok := strings.Cut(values["Reference ID"], "("); ok {
+		status.Source = strings.TrimSuffix(source, ")")
+	}
+	// "0.000011923 seconds fast of NTP time"
+	if fields := strings.Fields(values["System time"]); len(fields) >= 3 {
+		if seconds, err := strconv.ParseFloat(fields[0], 64); err == nil {
+			if fields[2] == "slow" {
+				seconds = -seconds
+			}
+			status.OffsetMillis = seconds * 1000
+		}
+	}
+	return status, true
+}
+
+// parseTimesyncStatus reads `timedatectl timesync-status`, whose offset
+// is reference minus local ("Offset: -1.136ms" means we are ahead).
+func parseTimesyncStatus(data []byte) (source string, offsetMillis float64) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch strings.TrimSpace(key) {
+		case "Server":
+			source = value
+		case "Offset":
+			if d, err := time.ParseDuration(value); err == nil {
+				offsetMillis = -float64(d) / float64(time.Millisecond)
+			}
+		}
+	}
+	return source, offsetMillis
+}
+
+// parseNtpqReadvar reads `ntpq -c rv`: comma-separated key=value pairs,
+// with status words such as "sync_ntp" on the first line. The offset is in
+// milliseconds.
+func parseNtpqReadvar(data []byte) (TimeSyncStatus, bool) {
+	text := strings.ReplaceAll(string(data), "\n", ",")
+	values := map[string]string{}
+	words := map[string]bool{}
+	for _, part := range strings.Split(text, ",") {
+		for _, field := range strings.Fields(part) {
+			if key, value, ok := strings.Cut(field, "="); ok {
+				values[key] = strings.Trim(value, `"`)
+			} else {
+				words[field] = true
+			}
+		}
+	}
+	if len(values) == 0 {
+		return TimeSyncStatus{}, false
+	}
+
+	status := TimeSyncStatus{Daemon: "ntpd", NTPEnabled: true}
+	status.Synchronized = (words["sync_ntp"] || words["sync_pps"] || words["sync_local"]) && !words["leap_alarm"]
+	status.Source = values["refid"]
+	if offset, err := strconv.ParseFloat(values["offset"], 64); err == nil {
+		status.OffsetMillis = offset
+	}
+	return status, true
+}
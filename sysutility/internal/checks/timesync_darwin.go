@@ -0,0 +1,24 @@
+//go:build darwin
+// +build darwin
+
+package checks
+
+import (
+	"os/exec"
+	"strings"
+)
+
+func checkTimeSync() *TimeSyncStatus {
+	status := &TimeSyncStatus{Daemon: "timed"}
+	// systemsetup needs root; without it NTPEnabled stays false
+	if out, err := exec.Command("systemsetup", "-getusingnetworktime").Output(); err == nil {
+		status.NTPEnabled = strings.Contains(string(out), ": On")
+	}
+	if out, err := exec.Command("systemsetup", "-getnetworktimeserver").Output(); err == nil {
+		_, server, _ := strings.Cut(strings.TrimSpace(string(out)), ": ")
+		status.Source = server
+	}
+	// timed does not expose its state; an enabled daemon is assumed in sync
+	status.Synchronized = status.NTPEnabled
+	return status
+}
@@ -0,0 +1,44 @@
+//go:build linux
+// +build linux
+
+package checks
+
+import "os/exec"
+
+func checkTimeSync() *TimeSyncStatus {
+	status := &TimeSyncStatus{}
+	if out, err := exec.Command("timedatectl", "show").Output(); err == nil {
+		props := parseSystemctlShow(out)
+		status.NTPEnabled = props["NTP"] == "yes"
+		status.Synchronized = props["NTPSynchronized"] == "yes"
+	}
+
+	switch {
+	case userProcessRunning(-1, "chronyd"):
+		if out, err := exec.Command("chronyc", "-n", "tracking").Output(); err == nil {
+			if chrony, ok := parseChronyTracking(out); ok {
+				// The kernel's view from timedatectl wins when available
+				chrony.Synchronized = chrony.Synchronized || status.Synchronized
+				return &chrony
+			}
+		}
+		status.Daemon = "chrony"
+
+	case userProcessRunning(-1, "ntpd"):
+		if out, err := exec.Command("ntpq", "-c", "rv").Output(); err == nil {
+			if ntp, ok := parseNtpqReadvar(out); ok {
+				ntp.Synchronized = ntp.Synchronized || status.Synchronized
+				return &ntp
+			}
+		}
+		status.Daemon = "ntpd"
+
+	// comm is truncated to 15 characters
+	case userProcessRunning(-1, "systemd-timesyn"):
+		status.Daemon = "systemd-timesyncd"
+		if out, err := exec.Command("timedatectl", "timesync-status").Output(); err == nil {
+			status.Source, status.OffsetMillis = parseTimesyncStatus(out)
+		}
+	}
+	return status
+}
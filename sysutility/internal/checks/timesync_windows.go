@@ -0,0 +1,37 @@
+//go:build windows
+// +build windows
+
+package checks
+
+import (
+	"os/exec"
+	"strings"
+)
+
+func checkTimeSync() *TimeSyncStatus {
+	out, err := exec.Command("w32tm", "/query", "/status").Output()
+	if err != nil {
+		// w32time is stopped
+		return &TimeSyncStatus{}
+	}
+	status := &TimeSyncStatus{Daemon: "w32time", NTPEnabled: true}
+	for _, line := range strings.Split(string(out), "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch strings.TrimSpace(key) {
+		case "Source":
+			status.Source = value
+		case "Leap Indicator":
+			// 3 is "not synchronized"
+			status.Synchronized = !strings.HasPrefix(value, "3")
+		}
+	}
+	// Without a time source w32time just free-runs on the local clock
+	if status.Source == "Local CMOS Clock" || status.Source == "Free-running System Clock" {
+		status.Synchronized = false
+	}
+	return status
+}
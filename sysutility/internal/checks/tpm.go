@@ -0,0 +1,55 @@
+package checks
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// TPMStatus describes the platform TPM and whether disk encryption is bound to it.
+type TPMStatus struct {
+	Present         bool   `json:"present"`
+	Version         string `json:"version,omitempty"`
+	Manufacturer    string `json:"manufacturer,omitempty"`
+	FirmwareVersion string `json:"firmware_version,omitempty"`
+
+	// LUKSTPM2Bound is true when at least one LUKS volume carries a
+	// systemd-tpm2 token, i.e. can be unlocked by the TPM.
+	LUKSTPM2Bound bool         `json:"luks_tpm2_bound"`
+	LUKSVolumes   []LUKSVolume `json:"luks_volumes,omitempty"`
+
+	// PCRs maps "<bank>:<index>" (e.g. "sha256:7") to the hex digest, when
+	// the kernel exposes them.
+	PCRs map[string]string `json:"pcrs,omitempty"`
+}
+
+type LUKSVolume struct {
+	Device    string `json:"device"`
+	Version   int    `json:"version"`
+	TPM2Token bool   `json:"tpm2_token"`
+}
+
+// tpmChanged compares TPM status keeping only the boot-time PCRs 0-7.
+// Runtime PCRs such as 10 (IMA) extend with every measured file.
+func tpmChanged(old, new *TPMStatus) bool {
+	if old == nil || new == nil {
+		return old != new
+	}
+	a, b := *old, *new
+	a.PCRs, b.PCRs = bootPCRs(a.PCRs), bootPCRs(b.PCRs)
+	return !reflect.DeepEqual(a, b)
+}
+
+func bootPCRs(pcrs map[string]string) map[string]string {
+	if pcrs == nil {
+		return nil
+	}
+	boot := map[string]string{}
+	for key, digest := range pcrs {
+		_, index, _ := strings.Cut(key, ":")
+		if n, err := strconv.Atoi(index); err == nil && n <= 7 {
+			boot[key] = digest
+		}
+	}
+	return boot
+}
@@ -0,0 +1,9 @@
+//go:build darwin
+// +build darwin
+
+package checks
+
+func checkTPM() *TPMStatus {
+	// Macs use the Secure Enclave rather than a TPM
+	return nil
+}
@@ -0,0 +1,270 @@
+//go:build linux
+// +build linux
+
+package checks
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const tpmSysfsDir = "/sys/class/tpm"
+
+func checkTPM() *TPMStatus {
+	status := &TPMStatus{}
+
+	devices, _ := filepath.Glob(filepath.Join(tpmSysfsDir, "tpm[0-9]*"))
+	if len(devices) > 0 {
+		dev := devices[0]
+		status.Present = true
+		status.Version = getTPMVersion(dev)
+
+		// TPM 1.2 chips publish their identity in the caps file
+		if data, err := os.ReadFile(filepath.Join(dev, "device", "caps")); err == nil {
+			manufacturer, firmware, version := parseTPMCaps(data)
+			status.Manufacturer = manufacturer
+			status.FirmwareVersion = firmware
+			if status.Version == "" {
+				status.Version = version
+			}
+		}
+
+		// TPM 2.0 has no caps file; ask tpm2-tools if it is installed
+		if status.Version == "2.0" && status.Manufacturer == "" {
+			if out, err := exec.Command("tpm2_getcap", "properties-fixed").Output(); err == nil {
+				status.Manufacturer, status.FirmwareVersion = parseTPM2Properties(out)
+			}
+		}
+
+		status.PCRs = readTPMPCRs(dev)
+	}
+
+	status.LUKSVolumes = findLUKSVolumes()
+	for _, vol := range status.LUKSVolumes {
+		if vol.TPM2Token {
+			status.LUKSTPM2Bound = true
+		}
+	}
+
+	return status
+}
+
+func getTPMVersion(dev string) string {
+	// Available since Linux 5.6
+	data, err := os.ReadFile(filepath.Join(dev, "tpm_version_major"))
+	if err == nil {
+		switch strings.TrimSpace(string(data)) {
+		case "1":
+			return "1.2"
+		case "2":
+			return "2.0"
+		}
+	}
+
+	// Older kernels only create the resource manager node for TPM 2.0
+	name := filepath.Base(dev)
+	if _, err := os.Stat(filepath.Join("/sys/class/tpmrm", "tpmrm"+strings.TrimPrefix(name, "tpm"))); err == nil {
+		return "2.0"
+	}
+	return ""
+}
+
+// parseTPMCaps extracts the manufacturer, firmware and TCG version from the
+// sysfs caps file of a TPM 1.2 device.
+func parseTPMCaps(data []byte) (manufacturer, firmware, version string) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch strings.TrimSpace(key) {
+		case "Manufacturer":
+			manufacturer = decodeTPMVendor(value)
+		case "Firmware version":
+			firmware = value
+		case "TCG version":
+			version = value
+		}
+	}
+	return manufacturer, firmware, version
+}
+
+// parseTPM2Properties reads the manufacturer and firmware version out of
+// `tpm2_getcap properties-fixed`.
+func parseTPM2Properties(data []byte) (manufacturer, firmware string) {
+	raw := map[string]string{}
+	var current string
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, " ") && strings.HasSuffix(line, ":") {
+			current = strings.TrimSuffix(line, ":")
+			continue
+		}
+		if key, value, ok := strings.Cut(strings.TrimSpace(line), ":"); ok && key == "raw" {
+			raw[current] = strings.TrimSpace(value)
+		}
+	}
+
+	if v, ok := raw["TPM2_PT_MANUFACTURER"]; ok {
+		manufacturer = decodeTPMVendor(v)
+	}
+
+	fw1, err1 := strconv.ParseUint(strings.TrimPrefix(raw["TPM2_PT_FIRMWARE_VERSION_1"], "0x"), 16, 32)
+	fw2, err2 := strconv.ParseUint(strings.TrimPrefix(raw["TPM2_PT_FIRMWARE_VERSION_2"], "0x"), 16, 32)
+	if err1 == nil {
+		firmware = fmt.Sprintf("%d.%d", fw1>>16, fw1&0xffff)
+		if err2 == nil {
+			firmware += fmt.Sprintf(".%d.%d", fw2>>16, fw2&0xffff)
+		}
+	}
+	return manufacturer, firmware
+}
+
+// decodeTPMVendor turns a TCG vendor ID such as 0x49465800 into its ASCII
+// form ("IFX"). Values that are not hex are returned unchanged.
+func decodeTPMVendor(value string) string {
+	fields := strings.Fields(value)
+	if len(fields) == 0 {
+		return value
+	}
+	id, err := strconv.ParseUint(strings.TrimPrefix(fields[0], "0x"), 16, 32)
+	if err != nil {
+		return value
+	}
+	b := make([]byte, 4)
+	binary.BigEndian.PutUint32(b, uint32(id))
+	return strings.TrimSpace(strings.TrimRight(string(b), "\x00"))
+}
+
+func readTPMPCRs(dev string) map[string]string {
+	pcrs := map[string]string{}
+
+	// Linux 5.12+ exposes one file per PCR and bank
+	banks, _ := filepath.Glob(filepath.Join(dev, "pcr-*"))
+	for _, bank := range banks {
+		alg := strings.TrimPrefix(filepath.Base(bank), "pcr-")
+		entries, err := os.ReadDir(bank)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if data, err := os.ReadFile(filepath.Join(bank, entry.Name())); err == nil {
+				pcrs[alg+":"+entry.Name()] = strings.ToLower(strings.TrimSpace(string(data)))
+			}
+		}
+	}
+
+	// TPM 1.2 drivers publish a single SHA-1 listing
+	if len(pcrs) == 0 {
+		if data, err := os.ReadFile(filepath.Join(dev, "device", "pcrs")); err == nil {
+			pcrs = parseTPM12PCRs(data)
+		}
+	}
+
+	if len(pcrs) == 0 {
+		return nil
+	}
+	return pcrs
+}
+
+// parseTPM12PCRs parses lines like "PCR-00: A8 5B ..." from the TPM 1.2 pcrs file.
+func parseTPM12PCRs(data []byte) map[string]string {
+	pcrs := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok || !strings.HasPrefix(key, "PCR-") {
+			continue
+		}
+		index, err := strconv.Atoi(strings.TrimPrefix(key, "PCR-"))
+		if err != nil {
+			continue
+		}
+		digest := strings.ToLower(strings.Join(strings.Fields(value), ""))
+		pcrs["sha1:"+strconv.Itoa(index)] = digest
+	}
+	return pcrs
+}
+
+func findLUKSVolumes() []LUKSVolume {
+	out, err := exec.Command("lsblk", "-rpno", "NAME,FSTYPE").Output()
+	if err != nil {
+		return nil
+	}
+
+	var volumes []LUKSVolume
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || fields[1] != "crypto_LUKS" {
+			continue
+		}
+		vol := LUKSVolume{Device: fields[0]}
+		if f, err := os.Open(fields[0]); err == nil {
+			vol.Version, vol.TPM2Token, _ = readLUKSHeader(f)
+			f.Close()
+		}
+		volumes = append(volumes, vol)
+	}
+	return volumes
+}
+
+// readLUKSHeader reads the LUKS header version and, for LUKS2, whether the
+// JSON metadata area contains a systemd-tpm2 token.
+func readLUKSHeader(r io.ReaderAt) (int, bool, error) {
+	hdr := make([]byte, 4096)
+	if _, err := r.ReadAt(hdr, 0); err != nil {
+		return 0, false, err
+	}
+	if !bytes.Equal(hdr[:6], []byte{'L', 'U', 'K', 'S', 0xba, 0xbe}) {
+		return 0, false, fmt.Errorf("not a LUKS header")
+	}
+
+	version := int(binary.BigEndian.Uint16(hdr[6:8]))
+	if version != 2 {
+		return version, false, nil
+	}
+
+	// The JSON area follows the 4 KiB binary header, up to hdr_size
+	hdrSize := binary.BigEndian.Uint64(hdr[8:16])
+	if hdrSize <= 4096 || hdrSize > 4<<20 {
+		return version, false, fmt.Errorf("invalid LUKS2 header size %d", hdrSize)
+	}
+	area := make([]byte, hdrSize-4096)
+	if _, err := r.ReadAt(area, 4096); err != nil {
+		return version, false, err
+	}
+
+	hasToken, err := luks2HasTPM2Token(bytes.TrimRight(area, "\x00"))
+	return version, hasToken, err
+}
+
+func luks2HasTPM2Token(metadata []byte) (bool, error) {
+	var doc struct {
+		Tokens map[string]struct {
+			Type string `json:"type"`
+		} `json:"tokens"`
+	}
+	if err := json.Unmarshal(metadata, &doc); err != nil {
+		return false, err
+	}
+	for _, token := range doc.Tokens {
+		if token.Type == "systemd-tpm2" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
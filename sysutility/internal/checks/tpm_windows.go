@@ -0,0 +1,9 @@
+//go:build windows
+// +build windows
+
+package checks
+
+func checkTPM() *TPMStatus {
+	// Not implemented yet; Get-Tpm requires an elevated shell
+	return nil
+}
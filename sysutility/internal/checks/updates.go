@@ -0,0 +1,33 @@
+package checks
+
+import "reflect"
+
+// UpdateStatus lists the package updates waiting to be installed.
+type UpdateStatus struct {
+	PackageManager string          `json:"package_manager"`
+	Pending        int             `json:"pending"`
+	Security       int             `json:"security"`
+	Packages       []PendingUpdate `json:"packages,omitempty"`
+
+	// IndexUpdatedAt is when the package indices were last downloaded.
+	IndexUpdatedAt  string `json:"index_updated_at,omitempty"`
+	IndexAgeSeconds int64  `json:"index_age_seconds,omitempty"`
+}
+
+type PendingUpdate struct {
+	Name             string `json:"name"`
+	InstalledVersion string `json:"installed_version,omitempty"`
+	CandidateVersion string `json:"candidate_version"`
+	Security         bool   `json:"security"`
+}
+
+// updatesChanged compares two update reports, ignoring the index age,
+// which grows on every scan.
+func updatesChanged(old, new *UpdateStatus) bool {
+	if old == nil || new == nil {
+		return old != new
+	}
+	a, b := *old, *new
+	a.IndexAgeSeconds, b.IndexAgeSeconds = 0, 0
+	return !reflect.DeepEqual(a, b)
+}
@@ -0,0 +1,261 @@
+//go:build linux
+// +build linux
+
+package checks
+
+import (
+	"bufio"
+	"bytes"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Where each package manager keeps the indices it last downloaded; their
+// modification time tells how fresh the update data is.
+var updateIndexPaths = map[string][]string{
+	"apt":    {"/var/lib/apt/lists/*_Release", "/var/lib/apt/lists/*_InRelease"},
+	"dnf":    {"/var/cache/dnf/*/repodata/repomd.xml", "/var/cache/libdnf5/*/repodata/repomd.xml"},
+	"yum":    {"/var/cache/yum/*/*/*/repomd.xml", "/var/cache/yum/*/repomd.xml"},
+	"zypper": {"/var/cache/zypp/raw/*/repodata/repomd.xml"},
+	"pacman": {"/var/lib/pacman/sync/*.db"},
+}
+
+// Commands that refresh the indices. pacman is left out on purpose:
+// syncing without upgrading invites partial upgrades.
+var updateRefreshCommands = map[string][]string{
+	"apt":    {"apt-get", "update", "-qq"},
+	"dnf":    {"dnf", "-q", "makecache"},
+	"yum":    {"yum", "-q", "makecache"},
+	"zypper": {"zypper", "--non-interactive", "--quiet", "refresh"},
+}
+
+// getPendingUpdates computes pending updates from the package indices
+// already on disk. Indices are only refreshed when the policy sets a
+// refresh interval and they are older than that.
+func getPendingUpdates(policy Policy) *UpdateStatus {
+	manager := detectPackageManager()
+	if manager == "" {
+		return nil
+	}
+
+	indexTime := newestModTime(updateIndexPaths[manager]...)
+	if refresh, ok := updateRefreshCommands[manager]; ok && policy.UpdateIndexRefreshHours > 0 {
+		maxAge := time.Duration(policy.UpdateIndexRefreshHours) * time.Hour
+		if indexTime.IsZero() || time.Since(indexTime) > maxAge {
+			if err := exec.Command(refresh[0], refresh[1:]...).Run(); err == nil {
+				indexTime = newestModTime(updateIndexPaths[manager]...)
+			}
+		}
+	}
+
+	var status *UpdateStatus
+	switch manager {
+	case "apt":
+		updates, err := aptPendingFromLists()
+		if err != nil {
+			return nil
+		}
+		status = newUpdateStatus("apt", updates)
+
+	case "dnf", "yum":
+		// -C keeps dnf/yum on its cache: no network, no metadata refresh
+		cmd := exec.Command(manager, "-C", "-q", "check-update")
+		out, err := cmd.Output()
+		// check-update exits with 100 when updates are available
+		if err != nil && cmd.ProcessState.ExitCode() != 100 {
+			return nil
+		}
+		updates := parseDnfCheckUpdate(out)
+		fillRPMInstalledVersions(updates)
+		if secOut, err := exec.Command(manager, "-C", "-q", "updateinfo", "list", "--security").Output(); err == nil {
+			markRPMSecurityUpdates(updates, secOut)
+		}
+		status = newUpdateStatus(manager, updates)
+
+	case "zypper":
+		out, err := exec.Command("zypper", "--non-interactive", "--quiet", "--no-refresh", "list-updates").Output()
+		if err != nil {
+			return nil
+		}
+		status = newUpdateStatus("zypper", parseZypperListUpdates(out))
+		// zypper tracks security fixes as patches rather than packages
+		if patchOut, err := exec.Command("zypper", "--non-interactive", "--quiet", "--no-refresh", "list-patches", "--category", "security").Output(); err == nil {
+			status.Security = len(parseZypperTable(patchOut))
+		}
+
+	case "pacman":
+		// pacman -Qu only reads the local sync databases
+		cmd := exec.Command("pacman", "-Qu")
+		out, err := cmd.Output()
+		// pacman -Qu exits with 1 when there is nothing to upgrade
+		if err != nil && cmd.ProcessState.ExitCode() != 1 {
+			return nil
+		}
+		updates := parsePacmanQu(out)
+		// arch-audit knows which upgrades fix security issues
+		if auditOut, err := exec.Command("arch-audit", "-q", "-u").Output(); err == nil {
+			markNamedSecurityUpdates(updates, strings.Fields(string(auditOut)))
+		}
+		status = newUpdateStatus("pacman", updates)
+	}
+
+	if !indexTime.IsZero() {
+		status.IndexUpdatedAt = indexTime.UTC().Format(time.RFC3339)
+		status.IndexAgeSeconds = int64(time.Since(indexTime).Seconds())
+	}
+	return status
+}
+
+func detectPackageManager() string {
+	for _, manager := range []string{"apt-get", "dnf", "yum", "zypper", "pacman"} {
+		if _, err := exec.LookPath(manager); err == nil {
+			return strings.TrimSuffix(manager, "-get")
+		}
+	}
+	return ""
+}
+
+func newUpdateStatus(manager string, updates []PendingUpdate) *UpdateStatus {
+	status := &UpdateStatus{PackageManager: manager, Pending: len(updates), Packages: updates}
+	for _, u := range updates {
+		if u.Security {
+			status.Security++
+		}
+	}
+	return status
+}
+
+// parseDnfCheckUpdate parses "name.arch  version  repo" lines of
+// dnf/yum check-update, stopping at the obsoletes section.
+func parseDnfCheckUpdate(data []byte) []PendingUpdate {
+	var updates []PendingUpdate
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "Obsoleting") {
+			break
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 3 || strings.HasPrefix(line, " ") {
+			continue
+		}
+		name := fields[0]
+		if dot := strings.LastIndex(name, "."); dot > 0 {
+			name = name[:dot]
+		}
+		updates = append(updates, PendingUpdate{Name: name, CandidateVersion: fields[1]})
+	}
+	return updates
+}
+
+func fillRPMInstalledVersions(updates []PendingUpdate) {
+	if len(updates) == 0 {
+		return
+	}
+	args := []string{"-q", "--qf", "%{NAME} %{VERSION}-%{RELEASE}\\n"}
+	for _, u := range updates {
+		args = append(args, u.Name)
+	}
+	// rpm exits non-zero if any package is missing; use what it printed
+	out, _ := exec.Command("rpm", args...).Output()
+
+	installed := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		if fields := strings.Fields(scanner.Text()); len(fields) == 2 {
+			installed[fields[0]] = fields[1]
+		}
+	}
+	for i := range updates {
+		updates[i].InstalledVersion = installed[updates[i].Name]
+	}
+}
+
+// markRPMSecurityUpdates flags updates named in `updateinfo list --security`
+// output, whose last column is a NEVRA such as openssl-libs-1:3.0.7-17.el9.x86_64.
+func markRPMSecurityUpdates(updates []PendingUpdate, data []byte) {
+	var nevras []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		if fields := strings.Fields(scanner.Text()); len(fields) >= 3 {
+			nevras = append(nevras, fields[len(fields)-1])
+		}
+	}
+	for i := range updates {
+		prefix := updates[i].Name + "-"
+		for _, nevra := range nevras {
+			rest := strings.TrimPrefix(nevra, prefix)
+			if rest != nevra && rest != "" && rest[0] >= '0' && rest[0] <= '9' {
+				updates[i].Security = true
+				break
+			}
+		}
+	}
+}
+
+// parseZypperTable splits the rows of a zypper "|"-separated table,
+// skipping the header and separator lines.
+func parseZypperTable(data []byte) [][]string {
+	var rows [][]string
+	header := true
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.Contains(line, "|") {
+			continue
+		}
+		if strings.HasPrefix(line, "--") || strings.Contains(line, "-+-") {
+			continue
+		}
+		if header {
+			header = false
+			continue
+		}
+		cols := strings.Split(line, "|")
+		for i := range cols {
+			cols[i] = strings.TrimSpace(cols[i])
+		}
+		rows = append(rows, cols)
+	}
+	return rows
+}
+
+// parseZypperListUpdates reads "S | Repository | Name | Current Version |
+// Available Version | Arch" rows.
+func parseZypperListUpdates(data []byte) []PendingUpdate {
+	var updates []PendingUpdate
+	for _, cols := range parseZypperTable(data) {
+		if len(cols) < 6 {
+			continue
+		}
+		updates = append(updates, PendingUpdate{Name: cols[2], InstalledVersion: cols[3], CandidateVersion: cols[4]})
+	}
+	return updates
+}
+
+// parsePacmanQu parses "name oldver -> newver" lines.
+func parsePacmanQu(data []byte) []PendingUpdate {
+	var updates []PendingUpdate
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 || fields[2] != "->" {
+			continue
+		}
+		updates = append(updates, PendingUpdate{Name: fields[0], InstalledVersion: fields[1], CandidateVersion: fields[3]})
+	}
+	return updates
+}
+
+func markNamedSecurityUpdates(updates []PendingUpdate, names []string) {
+	security := map[string]bool{}
+	for _, name := range names {
+		security[name] = true
+	}
+	for i := range updates {
+		if security[updates[i].Name] {
+			updates[i].Security = true
+		}
+	}
+}
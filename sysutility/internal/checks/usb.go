@@ -0,0 +1,67 @@
+package checks
+
+// USBStatus reports whether USB mass storage can be used on the machine and
+// which storage devices are attached.
+type USBStatus struct {
+	// StorageBlocked is true when new USB storage devices cannot be used,
+	// through the kernel module configuration or a USBGuard default-deny
+	// policy.
+	StorageBlocked bool `json:"storage_blocked"`
+	// Compliant is false when the policy requires blocking and storage is
+	// not blocked or a storage device is in use.
+	Compliant bool `json:"compliant"`
+
+	// ModuleBlacklisted stops udev from autoloading usb-storage; an explicit
+	// modprobe still works. ModuleDisabled ("install usb-storage /bin/false")
+	// stops both.
+	ModuleBlacklisted bool   `json:"module_blacklisted"`
+	ModuleDisabled    bool   `json:"module_disabled"`
+	ModuleLoaded      bool   `json:"module_loaded"`
+	ModuleSource      string `json:"module_source,omitempty"`
+
+	USBGuard *USBGuardStatus `json:"usbguard,omitempty"`
+
+	StorageDevices []USBDevice `json:"storage_devices,omitempty"`
+}
+
+type USBGuardStatus struct {
+	Running bool `json:"running"`
+	// ImplicitPolicyTarget applies to devices no rule matches: "allow",
+	// "block" or "reject".
+	ImplicitPolicyTarget string `json:"implicit_policy_target,omitempty"`
+	PresentDevicePolicy  string `json:"present_device_policy,omitempty"`
+	Rules                int    `json:"rules"`
+	// AllowsStorage is true when an allow rule matches the mass storage
+	// interface class.
+	AllowsStorage bool `json:"allows_storage"`
+}
+
+type USBDevice struct {
+	// Port is the sysfs bus path, e.g. "2-1.4".
+	Port         string `json:"port"`
+	VendorID     string `json:"vendor_id"`
+	ProductID    string `json:"product_id"`
+	Manufacturer string `json:"manufacturer,omitempty"`
+	Product      string `json:"product,omitempty"`
+	Driver       string `json:"driver,omitempty"`
+	Authorized   bool   `json:"authorized"`
+	// Allowed is true when the device is authorized and a storage driver
+	// is bound to it or can still be loaded for it.
+	Allowed bool `json:"allowed"`
+}
+
+// usbCompliant evaluates the status against the policy.
+func usbCompliant(status *USBStatus, policy Policy) bool {
+	if !policy.BlockUSBStorage {
+		return true
+	}
+	if !status.StorageBlocked {
+		return false
+	}
+	for _, dev := range status.StorageDevices {
+		if dev.Allowed {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,284 @@
+//go:build linux
+// +build linux
+
+package checks
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	usbDevicesDir    = "/sys/bus/usb/devices"
+	usbMassStorage   = "08"
+	usbGuardConfPath = "/etc/usbguard/usbguard-daemon.conf"
+)
+
+func checkUSB(policy Policy) *USBStatus {
+	status := &USBStatus{}
+
+	// modprobe reads the same directories as systemd-sysctl
+	for _, path := range configDropIns(sysctlConfigDirs, "modprobe.d/*.conf") {
+		blacklisted, disabled := parseModprobeConf(readFileOrNil(path), "usb_storage")
+		if blacklisted || disabled {
+			status.ModuleSource = path
+		}
+		status.ModuleBlacklisted = status.ModuleBlacklisted || blacklisted
+		status.ModuleDisabled = status.ModuleDisabled || disabled
+	}
+	for _, arg := range strings.Fields(string(readFileOrNil("/proc/cmdline"))) {
+		key, modules, _ := strings.Cut(arg, "=")
+		if key != "modprobe.blacklist" && key != "module_blacklist" {
+			continue
+		}
+		for _, module := range strings.Split(modules, ",") {
+			if normalizeModuleName(module) == "usb_storage" {
+				status.ModuleSource = "/proc/cmdline"
+				status.ModuleBlacklisted = true
+				// module_blacklist refuses the module even when asked for
+				status.ModuleDisabled = status.ModuleDisabled || key == "module_blacklist"
+			}
+		}
+	}
+	status.ModuleLoaded = fileExists("/sys/module/usb_storage")
+
+	status.USBGuard = checkUSBGuard()
+	guardBlocks := status.USBGuard != nil && status.USBGuard.Running &&
+		status.USBGuard.ImplicitPolicyTarget != "allow" && !status.USBGuard.AllowsStorage
+	// A blacklist only stops autoloading; once loaded the module binds
+	// every new device
+	status.StorageBlocked = status.ModuleDisabled ||
+		(status.ModuleBlacklisted && !status.ModuleLoaded) || guardBlocks
+
+	moduleBlocked := status.ModuleDisabled || (status.ModuleBlacklisted && !status.ModuleLoaded)
+	for _, dir := range sortedGlob(filepath.Join(usbDevicesDir, "*")) {
+		port := filepath.Base(dir)
+		// Interfaces ("1-2:1.0") and root hubs ("usb1") are not devices
+		if strings.Contains(port, ":") || strings.HasPrefix(port, "usb") {
+			continue
+		}
+		dev, ok := readUSBStorageDevice(dir)
+		if !ok {
+			continue
+		}
+		dev.Allowed = dev.Authorized && (dev.Driver != "" || !moduleBlocked)
+		status.StorageDevices = append(status.StorageDevices, dev)
+	}
+
+	status.Compliant = usbCompliant(status, policy)
+	return status
+}
+
+// readUSBStorageDevice reads a device's sysfs attributes when one of its
+// interfaces is of the mass storage class.
+func readUSBStorageDevice(dir string) (USBDevice, bool) {
+	attr := func(name string) string {
+		return strings.TrimSpace(string(readFileOrNil(filepath.Join(dir, name))))
+	}
+
+	storage := false
+	driver := ""
+	for _, iface := range sortedGlob(filepath.Join(dir, filepath.Base(dir)+":*")) {
+		class := strings.TrimSpace(string(readFileOrNil(filepath.Join(iface, "bInterfaceClass"))))
+		if class != usbMassStorage {
+			continue
+		}
+		storage = true
+		if link, err := os.Readlink(filepath.Join(iface, "driver")); err == nil {
+			driver = filepath.Base(link)
+		}
+	}
+	if !storage {
+		return USBDevice{}, false
+	}
+
+	return USBDevice{
+		Port:         filepath.Base(dir),
+		VendorID:     attr("idVendor"),
+		ProductID:    attr("idProduct"),
+		Manufacturer: attr("manufacturer"),
+		Product:      attr("product"),
+		Driver:       driver,
+		// Kernels without USB authorization support authorize everything
+		Authorized: attr("authorized") != "0",
+	}, true
+}
+
+// parseModprobeConf reports whether a modprobe.d file blacklists module or
+// replaces its loading with a no-op command such as /bin/false.
+func parseModprobeConf(data []byte, module string) (blacklisted, disabled bool) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	var line string
+	for scanner.Scan() {
+		text := scanner.Text()
+		// Trailing backslashes continue the line
+		if strings.HasSuffix(text, `\`) {
+			line += strings.TrimSuffix(text, `\`) + " "
+			continue
+		}
+		line += text
+		fields := strings.Fields(line)
+		line = ""
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "#") || normalizeModuleName(fields[1]) != module {
+			continue
+		}
+		switch fields[0] {
+		case "blacklist":
+			blacklisted = true
+		case "install":
+			if len(fields) > 2 && isNoopCommand(fields[2]) {
+				disabled = true
+			}
+		}
+	}
+	return blacklisted, disabled
+}
+
+func isNoopCommand(cmd string) bool {
+	switch filepath.Base(cmd) {
+	case "true", "false", "exit":
+		return true
+	}
+	return false
+}
+
+// normalizeModuleName maps "usb-storage" to "usb_storage"; the kernel
+// treats dashes and underscores in module names alike.
+func normalizeModuleName(name string) string {
+	return strings.ReplaceAll(name, "-", "_")
+}
+
+func checkUSBGuard() *USBGuardStatus {
+	data, err := os.ReadFile(usbGuardConfPath)
+	if err != nil {
+		return nil
+	}
+	conf := parseUSBGuardConf(data)
+	status := &USBGuardStatus{
+		Running:              userProcessRunning(-1, "usbguard-daemon"),
+		ImplicitPolicyTarget: valueOr(conf["ImplicitPolicyTarget"], "block"),
+		PresentDevicePolicy:  valueOr(conf["PresentDevicePolicy"], "apply-policy"),
+	}
+
+	// The rule files are usually root-only; without access the count is 0
+	ruleFile := conf["RuleFile"]
+	if ruleFile == "" {
+		ruleFile = "/etc/usbguard/rules.conf"
+	}
+	ruleFiles := []string{ruleFile}
+	if folder := conf["RuleFolder"]; folder != "" {
+		ruleFiles = append(ruleFiles, sortedGlob(filepath.Join(folder, "*.conf"))...)
+	}
+	for _, path := range ruleFiles {
+		rules, allowsStorage := parseUSBGuardRules(readFileOrNil(path))
+		status.Rules += rules
+		status.AllowsStorage = status.AllowsStorage || allowsStorage
+	}
+	return status
+}
+
+// parseUSBGuardConf reads the "Key=Value" lines of usbguard-daemon.conf.
+func parseUSBGuardConf(data []byte) map[string]string {
+	conf := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if key, value, ok := strings.Cut(line, "="); ok {
+			conf[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+	return conf
+}
+
+// parseUSBGuardRules counts the rules in a rules file and reports whether an
+// allow rule matches the mass storage interface class of any device, e.g.
+//
+//	allow with-interface equals { 08:*:* }
+func parseUSBGuardRules(data []byte) (int, bool) {
+	rules := 0
+	allowsStorage := false
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		switch fields[0] {
+		case "allow", "block", "reject":
+			rules++
+		default:
+			continue
+		}
+		if fields[0] == "allow" && !ruleIdentifiesDevice(fields[1:]) && ruleAllowsStorageInterface(fields[1:]) {
+			allowsStorage = true
+		}
+	}
+	return rules, allowsStorage
+}
+
+// usbGuardDeviceAttributes pin a rule to particular devices or ports.
+var usbGuardDeviceAttributes = []string{"id", "hash", "parent-hash", "serial", "via-port"}
+
+// ruleIdentifiesDevice reports whether a rule only matches particular
+// devices, like the ones `usbguard generate-policy` writes for built-in
+// card readers or an allowlist of approved sticks. Such rules do not open
+// the machine to storage devices in general.
+func ruleIdentifiesDevice(fields []string) bool {
+	for i, field := range fields {
+		if !containsString(usbGuardDeviceAttributes, field) {
+			continue
+		}
+		// "id *:*" matches every device
+		if field == "id" && i+1 < len(fields) && fields[i+1] == "*:*" {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// ruleAllowsStorageInterface looks at the with-interface operand of a rule,
+// either one "class:subclass:protocol" triplet or a set such as
+// "one-of { 08:06:50 08:06:62 }", for a triplet of the mass storage class
+// or a wildcard class.
+func ruleAllowsStorageInterface(fields []string) bool {
+	for i, field := range fields {
+		if field != "with-interface" {
+			continue
+		}
+		rest := fields[i+1:]
+		operator := ""
+		if len(rest) > 0 && !strings.Contains(rest[0], ":") && rest[0] != "{" {
+			operator, rest = rest[0], rest[1:]
+		}
+		if operator == "none-of" {
+			return false
+		}
+		var triplets []string
+		if len(rest) > 0 && strings.HasPrefix(rest[0], "{") {
+			for _, tok := range rest {
+				if trimmed := strings.Trim(tok, "{}"); trimmed != "" {
+					triplets = append(triplets, trimmed)
+				}
+				if strings.HasSuffix(tok, "}") {
+					break
+				}
+			}
+		} else if len(rest) > 0 {
+			triplets = append(triplets, rest[0])
+		}
+		for _, triplet := range triplets {
+			class, _, _ := strings.Cut(triplet, ":")
+			if class == usbMassStorage || class == "*" {
+				return true
+			}
+		}
+	}
+	return false
+}
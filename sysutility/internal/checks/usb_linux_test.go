@@ -0,0 +1,51 @@
+package checks
+
+import "testing"
+
+func TestParseUSBGuardRules(t *testing.T) {
+	tests := []struct {
+		name          string
+		rules         string
+		wantRules     int
+		wantAllowsAny bool
+	}{
+		{
+			name: "generate-policy output with a built-in card reader",
+			rules: `allow id 1d6b:0002 serial "0000:00:14.0" name "xHCI Host Controller" hash "jEP/6WzviqdJ5VSeTUY8PatCNBKeaREvo2OqdplND/o=" parent-hash "G1ehGQdrl3dJ9HvW9w2HdC//pk87pKzFE1WY25bq8k4=" with-interface 09:00:00 with-connect-type ""
+allow id 0bda:0129 serial "20100201396000000" name "USB2.0-CRW" hash "vRZVQkNnIoJmDGPwSQKyEibtSsNC4O0dA4xSo5P7JeE=" parent-hash "jEP/6WzviqdJ5VSeTUY8PatCNBKeaREvo2OqdplND/o=" via-port "1-3" with-interface equals { 08:06:50 } with-connect-type "hardwired"
+allow id 0408:5365 serial "01.00.00" name "HD Webcam" hash "mG9Jw5q1a0s6X+1nB3b2lBcvQ4VQv0c7nJm2q0pC8oE=" parent-hash "jEP/6WzviqdJ5VSeTUY8PatCNBKeaREvo2OqdplND/o=" with-interface { 0e:01:00 0e:02:00 0e:02:00 } with-connect-type "hardwired"
+`,
+			wantRules: 3,
+		},
+		{
+			name:      "approved stick allowlist",
+			rules:     "allow id 0781:5567 serial \"4C530001231219117142\" with-interface equals { 08:06:50 }\nblock with-interface one-of { 08:*:* }\n",
+			wantRules: 2,
+		},
+		{
+			name:          "storage class allowed for every device",
+			rules:         "allow with-interface equals { 08:*:* }\n",
+			wantRules:     1,
+			wantAllowsAny: true,
+		},
+		{
+			name:          "wildcard id does not pin a device",
+			rules:         "allow id *:* with-interface 08:06:50\n",
+			wantRules:     1,
+			wantAllowsAny: true,
+		},
+		{
+			name:      "storage excluded",
+			rules:     "# comment\nallow with-interface none-of { 08:*:* }\n",
+			wantRules: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules, allows := parseUSBGuardRules([]byte(tt.rules))
+			if rules != tt.wantRules || allows != tt.wantAllowsAny {
+				t.Errorf("got (%d, %v), want (%d, %v)", rules, allows, tt.wantRules, tt.wantAllowsAny)
+			}
+		})
+	}
+}
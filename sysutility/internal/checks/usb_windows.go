@@ -0,0 +1,32 @@
+//go:build windows
+// +build windows
+
+package checks
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// usbStorKey is the USB mass storage driver service. A Start value of 4
+// disables it, which is what the usual DLP group policy sets.
+const usbStorKey = `HKLM\SYSTEM\CurrentControlSet\Services\USBSTOR`
+
+func checkUSB(policy Policy) *USBStatus {
+	status := &USBStatus{}
+	out, err := exec.Command("reg", "query", usbStorKey, "/v", "Start").Output()
+	if err != nil {
+		return nil
+	}
+	// "    Start    REG_DWORD    0x4"
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 3 && fields[0] == "Start" {
+			status.ModuleDisabled = fields[2] == "0x4"
+			status.ModuleSource = usbStorKey
+		}
+	}
+	status.StorageBlocked = status.ModuleDisabled
+	status.Compliant = usbCompliant(status, policy)
+	return status
+}
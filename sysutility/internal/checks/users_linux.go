@@ -0,0 +1,95 @@
+//go:build linux
+// +build linux
+
+package checks
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// localUser is an account that belongs to a person rather than a service.
+type localUser struct {
+	Name string
+	UID  int
+	Home string
+}
+
+// humanUsers returns the accounts in the login.defs UID range that have a
+// usable shell, plus anyone with an open logind session (which also covers
+// directory users that are not in /etc/passwd).
+func humanUsers() []localUser {
+	uidMin, uidMax := 1000, 60000
+	if data, err := os.ReadFile("/etc/login.defs"); err == nil {
+		uidMin, uidMax = parseLoginDefsUIDRange(data, uidMin, uidMax)
+	}
+
+	var users []localUser
+	seen := map[int]bool{}
+	if data, err := os.ReadFile("/etc/passwd"); err == nil {
+		for _, u := range parsePasswdUsers(data, uidMin, uidMax) {
+			seen[u.UID] = true
+			users = append(users, u)
+		}
+	}
+
+	for _, session := range listSessions() {
+		if seen[session.UID] || session.UID < uidMin || session.Home == "" {
+			continue
+		}
+		seen[session.UID] = true
+		users = append(users, localUser{Name: session.User, UID: session.UID, Home: session.Home})
+	}
+
+	sort.Slice(users, func(i, j int) bool { return users[i].UID < users[j].UID })
+	return users
+}
+
+// parsePasswdUsers returns accounts from /etc/passwd whose UID falls in
+// [uidMin, uidMax] and whose shell allows logins.
+func parsePasswdUsers(data []byte, uidMin, uidMax int) []localUser {
+	var users []localUser
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Split(scanner.Text(), ":")
+		if len(fields) < 7 {
+			continue
+		}
+		uid, err := strconv.Atoi(fields[2])
+		if err != nil || uid < uidMin || uid > uidMax {
+			continue
+		}
+		shell := fields[6]
+		if strings.HasSuffix(shell, "/nologin") || strings.HasSuffix(shell, "/false") {
+			continue
+		}
+		users = append(users, localUser{Name: fields[0], UID: uid, Home: fields[5]})
+	}
+	return users
+}
+
+// parseLoginDefsUIDRange reads UID_MIN and UID_MAX from login.defs.
+func parseLoginDefsUIDRange(data []byte, uidMin, uidMax int) (int, int) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		n, err := strconv.Atoi(fields[1])
+		if err != nil {
+			continue
+		}
+		switch fields[0] {
+		case "UID_MIN":
+			uidMin = n
+		case "UID_MAX":
+			uidMax = n
+		}
+	}
+	return uidMin, uidMax
+}
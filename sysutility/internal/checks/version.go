@@ -0,0 +1,242 @@
+package checks
+
+import (
+	"strconv"
+	"strings"
+)
+
+// compareDpkgVersions orders two Debian package versions
+// ([epoch:]upstream[-revision]) the way dpkg does. It returns -1, 0 or 1.
+func compareDpkgVersions(a, b string) int {
+	epochA, upstreamA, revisionA := splitDpkgVersion(a)
+	epochB, upstreamB, revisionB := splitDpkgVersion(b)
+
+	if epochA != epochB {
+		if epochA < epochB {
+			return -1
+		}
+		return 1
+	}
+	if c := dpkgVerRevCmp(upstreamA, upstreamB); c != 0 {
+		return c
+	}
+	return dpkgVerRevCmp(revisionA, revisionB)
+}
+
+func splitDpkgVersion(v string) (int, string, string) {
+	epoch := 0
+	if i := strings.IndexByte(v, ':'); i >= 0 {
+		epoch, _ = strconv.Atoi(v[:i])
+		v = v[i+1:]
+	}
+	revision := ""
+	if i := strings.LastIndexByte(v, '-'); i >= 0 {
+		revision = v[i+1:]
+		v = v[:i]
+	}
+	return epoch, v, revision
+}
+
+// dpkgOrder gives the sort weight of a character in the non-digit part of
+// a version: "~" sorts before everything, even the end of the string, and
+// letters sort before other symbols.
+func dpkgOrder(c byte) int {
+	switch {
+	case c == '~':
+		return -1
+	case c >= '0' && c <= '9':
+		return 0
+	case c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+		return int(c)
+	default:
+		return int(c) + 256
+	}
+}
+
+func dpkgVerRevCmp(a, b string) int {
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		// Non-digit prefix
+		for (i < len(a) && !isDigit(a[i])) || (j < len(b) && !isDigit(b[j])) {
+			ac, bc := 0, 0
+			if i < len(a) {
+				ac = dpkgOrder(a[i])
+			}
+			if j < len(b) {
+				bc = dpkgOrder(b[j])
+			}
+			if ac != bc {
+				return sign(ac - bc)
+			}
+			i++
+			j++
+		}
+
+		// Digit run, compared numerically
+		for i < len(a) && a[i] == '0' {
+			i++
+		}
+		for j < len(b) && b[j] == '0' {
+			j++
+		}
+		firstDiff := 0
+		for i < len(a) && isDigit(a[i]) && j < len(b) && isDigit(b[j]) {
+			if firstDiff == 0 {
+				firstDiff = int(a[i]) - int(b[j])
+			}
+			i++
+			j++
+		}
+		if i < len(a) && isDigit(a[i]) {
+			return 1
+		}
+		if j < len(b) && isDigit(b[j]) {
+			return -1
+		}
+		if firstDiff != 0 {
+			return sign(firstDiff)
+		}
+	}
+	return 0
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
+
+// compareRPMVersions orders two [epoch:]version[-release] strings the way
+// rpm does. It returns -1, 0 or 1.
+func compareRPMVersions(a, b string) int {
+	epochA, versionA, releaseA := splitRPMVersion(a)
+	epochB, versionB, releaseB := splitRPMVersion(b)
+
+	if epochA != epochB {
+		if epochA < epochB {
+			return -1
+		}
+		return 1
+	}
+	if c := rpmVerCmp(versionA, versionB); c != 0 {
+		return c
+	}
+	// A missing release matches any release
+	if releaseA == "" || releaseB == "" {
+		return 0
+	}
+	return rpmVerCmp(releaseA, releaseB)
+}
+
+func splitRPMVersion(v string) (int, string, string) {
+	epoch := 0
+	if i := strings.IndexByte(v, ':'); i >= 0 {
+		epoch, _ = strconv.Atoi(v[:i])
+		v = v[i+1:]
+	}
+	release := ""
+	if i := strings.LastIndexByte(v, '-'); i >= 0 {
+		release = v[i+1:]
+		v = v[:i]
+	}
+	return epoch, v, release
+}
+
+// rpmVerCmp is a port of rpmvercmp(): versions are compared segment by
+// segment, numeric segments beat alphabetic ones, "~" sorts before
+// anything and "^" sorts after the base version but before any addition.
+func rpmVerCmp(a, b string) int {
+	if a == b {
+		return 0
+	}
+	isAlnum := func(c byte) bool {
+		return isDigit(c) || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+	}
+
+	for len(a) > 0 || len(b) > 0 {
+		for len(a) > 0 && !isAlnum(a[0]) && a[0] != '~' && a[0] != '^' {
+			a = a[1:]
+		}
+		for len(b) > 0 && !isAlnum(b[0]) && b[0] != '~' && b[0] != '^' {
+			b = b[1:]
+		}
+
+		if strings.HasPrefix(a, "~") || strings.HasPrefix(b, "~") {
+			if !strings.HasPrefix(a, "~") {
+				return 1
+			}
+			if !strings.HasPrefix(b, "~") {
+				return -1
+			}
+			a, b = a[1:], b[1:]
+			continue
+		}
+
+		if strings.HasPrefix(a, "^") || strings.HasPrefix(b, "^") {
+			if a == "" {
+				return -1
+			}
+			if b == "" {
+				return 1
+			}
+			if !strings.HasPrefix(a, "^") {
+				return 1
+			}
+			if !strings.HasPrefix(b, "^") {
+				return -1
+			}
+			a, b = a[1:], b[1:]
+			continue
+		}
+
+		if a == "" || b == "" {
+			break
+		}
+
+		numeric := isDigit(a[0])
+		take := func(s string) (string, string) {
+			i := 0
+			for i < len(s) && (numeric && isDigit(s[i]) || !numeric && isAlnum(s[i]) && !isDigit(s[i])) {
+				i++
+			}
+			return s[:i], s[i:]
+		}
+		var segA, segB string
+		segA, a = take(a)
+		segB, b = take(b)
+
+		if segB == "" {
+			if numeric {
+				return 1
+			}
+			return -1
+		}
+
+		if numeric {
+			segA = strings.TrimLeft(segA, "0")
+			segB = strings.TrimLeft(segB, "0")
+			if len(segA) != len(segB) {
+				return sign(len(segA) - len(segB))
+			}
+		}
+		if c := strings.Compare(segA, segB); c != 0 {
+			return c
+		}
+	}
+
+	switch {
+	case a == "" && b == "":
+		return 0
+	case a != "":
+		return 1
+	}
+	return -1
+}
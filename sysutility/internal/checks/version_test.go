@@ -0,0 +1,115 @@
+package checks
+
+import "testing"
+
+func TestCompareDpkgVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.0", "1.0", 0},
+		{"1.0", "1.1", -1},
+		{"2.0", "1.9", 1},
+
+		// Epochs
+		{"1:1.0", "2.0", 1},
+		{"0:1.0", "1.0", 0},
+		{"1:1.0-1", "1:1.0-2", -1},
+		{"2:0.1", "1:9.9", 1},
+
+		// Tilde sorts before everything, even the end of the string
+		{"1.0~rc1", "1.0", -1},
+		{"1.0~rc1", "1.0~rc2", -1},
+		{"1.0~~", "1.0~", -1},
+		{"1.0~rc1-1", "1.0-1", -1},
+		{"2.30-0ubuntu1~22.04", "2.30-0ubuntu1", -1},
+
+		// Letters mixed with digits; letters sort before other symbols
+		{"1.0a", "1.0", 1},
+		{"1.2a3", "1.2b1", -1},
+		{"7.6p2-4", "7.6-0", 1},
+		{"1.0+b1", "1.0", 1},
+		{"1.0+dfsg", "1.0a", 1},
+		{"1.0-1ubuntu1", "1.0-1", 1},
+		{"1.0-1ubuntu0.1", "1.0-1ubuntu1", -1},
+
+		// Leading zeros do not count
+		{"1.01", "1.1", 0},
+		{"1.001-01", "1.1-1", 0},
+		{"1.010", "1.9", 1},
+
+		// A missing revision compares like an empty one
+		{"1.0", "1.0-1", -1},
+		{"1.0", "1.0-0", 0},
+		{"3.0.2-0ubuntu1.15", "3.0.2", 1},
+	}
+	for _, tt := range tests {
+		if got := compareDpkgVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareDpkgVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+		if got := compareDpkgVersions(tt.b, tt.a); got != -tt.want {
+			t.Errorf("compareDpkgVersions(%q, %q) = %d, want %d", tt.b, tt.a, got, -tt.want)
+		}
+	}
+}
+
+func TestCompareRPMVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.0", "1.0", 0},
+		{"1.0", "2.0", -1},
+		{"2.0.1", "2.0", 1},
+
+		// Epochs
+		{"1:1.0-1", "2.0-1", 1},
+		{"0:1.0-1", "1.0-1", 0},
+		{"1:2.0-1", "2:1.0-1", -1},
+
+		// Tilde sorts before the base version
+		{"1.0~rc1", "1.0", -1},
+		{"1.0~rc1", "1.0~rc2", -1},
+		{"1.0~rc1~git123", "1.0~rc1", -1},
+		{"1.0~rc1-1", "1.0-1", -1},
+
+		// Caret sorts after the base version but before any addition
+		{"1.0^", "1.0", 1},
+		{"1.0^git1", "1.0", 1},
+		{"1.0^git1", "1.01", -1},
+		{"1.0^20160101", "1.0.1", -1},
+		{"1.0^20160101^git1", "1.0^20160101", 1},
+		{"1.0~rc1^git1", "1.0~rc1", 1},
+		{"1.0^git1~pre", "1.0^git1", -1},
+
+		// Letters mixed with digits; numeric segments beat alphabetic ones
+		{"2.0.1a", "2.0.1", 1},
+		{"5.5p1", "5.5p2", -1},
+		{"5.5p10", "5.5p1", 1},
+		{"5.5p2", "5.6p1", -1},
+		{"10xyz", "10.1xyz", -1},
+		{"xyz10", "xyz10.1", -1},
+		{"xyz.4", "8", -1},
+		{"6.0.rc1", "6.0", 1},
+		{"10b2", "10a1", 1},
+		{"1.0aa", "1.0ab", -1},
+
+		// Leading zeros do not count
+		{"1.05", "1.5", 0},
+		{"1.0010", "1.9", 1},
+
+		// A missing release matches any release
+		{"1.0", "1.0-1", 0},
+		{"1.0", "1.1-1", -1},
+		{"1.0-1.el9", "1.0-2.el9", -1},
+		{"5.14.0-427.13.1.el9_4", "5.14.0-427.el9", 1},
+	}
+	for _, tt := range tests {
+		if got := compareRPMVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareRPMVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+		if got := compareRPMVersions(tt.b, tt.a); got != -tt.want {
+			t.Errorf("compareRPMVersions(%q, %q) = %d, want %d", tt.b, tt.a, got, -tt.want)
+		}
+	}
+}
@@ -0,0 +1,529 @@
+package checks
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"sysutility/internal/inventory"
+)
+
+// defaultAdvisoryDir is where operators drop OSV JSON files or OVAL XML
+// exports when the policy does not name another directory.
+const defaultAdvisoryDir = "/var/lib/sysutility/advisories"
+
+// maxReportedFindings bounds the findings carried in a report. A release
+// OVAL export with unfixed issues matches thousands of packages, which
+// would make every report upload huge.
+const maxReportedFindings = 200
+
+// VulnerabilityStatus lists installed packages with known advisories.
+type VulnerabilityStatus struct {
+	AdvisoryDir      string `json:"advisory_dir"`
+	AdvisoriesLoaded int    `json:"advisories_loaded"`
+	// TotalFindings and BySeverity count every finding; Findings holds
+	// the most severe ones and is Truncated when some were left out.
+	TotalFindings int                    `json:"total_findings"`
+	BySeverity    map[string]int         `json:"by_severity,omitempty"`
+	Findings      []VulnerabilityFinding `json:"findings,omitempty"`
+	Truncated     bool                   `json:"truncated,omitempty"`
+}
+
+type VulnerabilityFinding struct {
+	Package          string   `json:"package"`
+	InstalledVersion string   `json:"installed_version"`
+	FixedVersion     string   `json:"fixed_version,omitempty"`
+	Advisory         string   `json:"advisory"`
+	CVEs             []string `json:"cves,omitempty"`
+	Severity         string   `json:"severity,omitempty"`
+}
+
+// severityRanks orders the severity words used by OSV, OVAL and the Debian
+// security tracker. Anything else, such as a CVSS vector, is "unknown".
+var severityRanks = map[string]int{
+	"critical":    5,
+	"important":   4,
+	"high":        4,
+	"moderate":    3,
+	"medium":      3,
+	"low":         2,
+	"negligible":  1,
+	"unimportant": 1,
+}
+
+// summarizeFindings counts findings by severity and keeps the
+// maxReportedFindings most severe.
+func summarizeFindings(status *VulnerabilityStatus, findings []VulnerabilityFinding) {
+	status.TotalFindings = len(findings)
+	if len(findings) == 0 {
+		return
+	}
+	status.BySeverity = map[string]int{}
+	for _, f := range findings {
+		severity := strings.ToLower(f.Severity)
+		if _, ok := severityRanks[severity]; !ok {
+			severity = "unknown"
+		}
+		status.BySeverity[severity]++
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		return severityRanks[strings.ToLower(findings[i].Severity)] > severityRanks[strings.ToLower(findings[j].Severity)]
+	})
+	if len(findings) > maxReportedFindings {
+		findings = findings[:maxReportedFindings]
+		status.Truncated = true
+	}
+	status.Findings = findings
+}
+
+// advisory is the common form OSV records and OVAL definitions are
+// loaded into.
+type advisory struct {
+	ID       string
+	CVEs     []string
+	Severity string
+	Affected []affectedPackage
+}
+
+type affectedPackage struct {
+	// Ecosystem is the OSV ecosystem, e.g. "Debian:12". OVAL exports are
+	// specific to one release and leave it empty.
+	Ecosystem string
+	Name      string
+	Events    []versionEvent
+	Versions  []string
+}
+
+// versionEvent is one OSV range event; exactly one field is set.
+type versionEvent struct {
+	Introduced   string `json:"introduced,omitempty"`
+	Fixed        string `json:"fixed,omitempty"`
+	LastAffected string `json:"last_affected,omitempty"`
+}
+
+// osvEcosystemIDs maps OSV ecosystem names to os-release IDs.
+var osvEcosystemIDs = map[string][]string{
+	"Debian":      {"debian"},
+	"Ubuntu":      {"ubuntu"},
+	"AlmaLinux":   {"almalinux"},
+	"Rocky Linux": {"rocky"},
+	"Red Hat":     {"rhel"},
+	"openSUSE":    {"opensuse-leap", "opensuse-tumbleweed"},
+	"SUSE":        {"sles", "sled"},
+	"Mageia":      {"mageia"},
+}
+
+var advisoryCache struct {
+	sync.Mutex
+	key        string
+	advisories []advisory
+}
+
+// loadAdvisories reads every OSV (*.json) and OVAL (*.xml) file under dir.
+// The result is cached until a file is added, removed, renamed or
+// rewritten.
+func loadAdvisories(dir string) []advisory {
+	var files []string
+	var key strings.Builder
+	key.WriteString(dir)
+	filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		if ext := filepath.Ext(path); ext == ".json" || ext == ".xml" {
+			files = append(files, path)
+			if info, err := d.Info(); err == nil {
+				fmt.Fprintf(&key, "\x00%s\x00%d\x00%d", path, info.Size(), info.ModTime().UnixNano())
+			}
+		}
+		return nil
+	})
+
+	advisoryCache.Lock()
+	defer advisoryCache.Unlock()
+	if advisoryCache.key == key.String() && advisoryCache.advisories != nil {
+		return advisoryCache.advisories
+	}
+
+	advisories := []advisory{}
+	for _, path := range files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		if filepath.Ext(path) == ".json" {
+			advisories = append(advisories, parseOSV(data)...)
+		} else {
+			advisories = append(advisories, parseOVAL(data)...)
+		}
+	}
+
+	advisoryCache.key = key.String()
+	advisoryCache.advisories = advisories
+	return advisories
+}
+
+type osvRecord struct {
+	ID       string   `json:"id"`
+	Aliases  []string `json:"aliases"`
+	Upstream []string `json:"upstream"`
+	Severity []struct {
+		Score string `json:"score"`
+	} `json:"severity"`
+	Affected []struct {
+		Package struct {
+			Ecosystem string `json:"ecosystem"`
+			Name      string `json:"name"`
+		} `json:"package"`
+		Ranges []struct {
+			Type   string         `json:"type"`
+			Events []versionEvent `json:"events"`
+		} `json:"ranges"`
+		Versions         []string `json:"versions"`
+		DatabaseSpecific struct {
+			Severity string `json:"severity"`
+		} `json:"database_specific"`
+		EcosystemSpecific struct {
+			Severity string `json:"severity"`
+			Urgency  string `json:"urgency"`
+		} `json:"ecosystem_specific"`
+	} `json:"affected"`
+	DatabaseSpecific struct {
+		Severity string `json:"severity"`
+	} `json:"database_specific"`
+}
+
+// parseOSV accepts a single OSV record or an array of them.
+func parseOSV(data []byte) []advisory {
+	var records []osvRecord
+	if err := json.Unmarshal(data, &records); err != nil {
+		var record osvRecord
+		if err := json.Unmarshal(data, &record); err != nil {
+			return nil
+		}
+		records = []osvRecord{record}
+	}
+
+	var advisories []advisory
+	for _, r := range records {
+		a := advisory{ID: r.ID, Severity: r.DatabaseSpecific.Severity}
+		for _, id := range append(append([]string{r.ID}, r.Aliases...), r.Upstream...) {
+			if strings.HasPrefix(id, "CVE-") && !containsString(a.CVEs, id) {
+				a.CVEs = append(a.CVEs, id)
+			}
+		}
+		if a.Severity == "" && len(r.Severity) > 0 {
+			a.Severity = r.Severity[0].Score
+		}
+
+		for _, aff := range r.Affected {
+			pkg := affectedPackage{
+				Ecosystem: aff.Package.Ecosystem,
+				Name:      aff.Package.Name,
+				Versions:  aff.Versions,
+			}
+			for _, rng := range aff.Ranges {
+				// SEMVER and GIT ranges do not apply to distro packages
+				if rng.Type == "ECOSYSTEM" {
+					pkg.Events = append(pkg.Events, rng.Events...)
+				}
+			}
+			for _, s := range []string{aff.EcosystemSpecific.Severity, aff.DatabaseSpecific.Severity, aff.EcosystemSpecific.Urgency} {
+				if a.Severity == "" && s != "" {
+					a.Severity = s
+				}
+			}
+			a.Affected = append(a.Affected, pkg)
+		}
+		advisories = append(advisories, a)
+	}
+	return advisories
+}
+
+type ovalDocument struct {
+	Definitions []ovalDefinition `xml:"definitions>definition"`
+	Tests       struct {
+		Items []ovalTest `xml:",any"`
+	} `xml:"tests"`
+	Objects struct {
+		Items []ovalObject `xml:",any"`
+	} `xml:"objects"`
+	States struct {
+		Items []ovalState `xml:",any"`
+	} `xml:"states"`
+	Variables struct {
+		Items []ovalVariable `xml:",any"`
+	} `xml:"variables"`
+}
+
+type ovalDefinition struct {
+	ID         string `xml:"id,attr"`
+	Class      string `xml:"class,attr"`
+	Title      string `xml:"metadata>title"`
+	References []struct {
+		Source string `xml:"source,attr"`
+		RefID  string `xml:"ref_id,attr"`
+	} `xml:"metadata>reference"`
+	AdvisoryCVEs []string     `xml:"metadata>advisory>cve"`
+	Severity     string       `xml:"metadata>advisory>severity"`
+	Criteria     ovalCriteria `xml:"criteria"`
+}
+
+type ovalCriteria struct {
+	Criteria   []ovalCriteria `xml:"criteria"`
+	Criterions []struct {
+		TestRef string `xml:"test_ref,attr"`
+	} `xml:"criterion"`
+}
+
+type ovalTest struct {
+	XMLName xml.Name
+	ID      string `xml:"id,attr"`
+	Object  struct {
+		Ref string `xml:"object_ref,attr"`
+	} `xml:"object"`
+	State struct {
+		Ref string `xml:"state_ref,attr"`
+	} `xml:"state"`
+}
+
+type ovalObject struct {
+	XMLName xml.Name
+	ID      string `xml:"id,attr"`
+	Name    struct {
+		Value  string `xml:",chardata"`
+		VarRef string `xml:"var_ref,attr"`
+	} `xml:"name"`
+}
+
+type ovalState struct {
+	XMLName xml.Name
+	ID      string `xml:"id,attr"`
+	EVR     struct {
+		Value     string `xml:",chardata"`
+		Operation string `xml:"operation,attr"`
+	} `xml:"evr"`
+}
+
+type ovalVariable struct {
+	ID     string   `xml:"id,attr"`
+	Values []string `xml:"value"`
+}
+
+// parseOVAL converts the vulnerability definitions of a Debian/Ubuntu (or
+// RHEL) OVAL export. Each dpkginfo/rpminfo test becomes an affected
+// package, fixed at the "less than" EVR of its state; a test without a
+// state means no fix is available. Other tests (release checks) are
+// ignored, since an export only covers one release.
+func parseOVAL(data []byte) []advisory {
+	var doc ovalDocument
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil
+	}
+
+	tests := map[string]ovalTest{}
+	for _, t := range doc.Tests.Items {
+		tests[t.ID] = t
+	}
+	objects := map[string][]string{}
+	variables := map[string][]string{}
+	for _, v := range doc.Variables.Items {
+		variables[v.ID] = v.Values
+	}
+	for _, o := range doc.Objects.Items {
+		if o.XMLName.Local != "dpkginfo_object" && o.XMLName.Local != "rpminfo_object" {
+			continue
+		}
+		if o.Name.VarRef != "" {
+			objects[o.ID] = variables[o.Name.VarRef]
+		} else if name := strings.TrimSpace(o.Name.Value); name != "" {
+			objects[o.ID] = []string{name}
+		}
+	}
+	states := map[string]string{}
+	for _, s := range doc.States.Items {
+		if s.EVR.Operation == "less than" {
+			states[s.ID] = strings.TrimSpace(s.EVR.Value)
+		}
+	}
+
+	var advisories []advisory
+	for _, def := range doc.Definitions {
+		if def.Class != "vulnerability" && def.Class != "patch" {
+			continue
+		}
+		a := advisory{ID: def.ID, Severity: def.Severity}
+		for _, ref := range def.References {
+			if ref.Source == "CVE" && !containsString(a.CVEs, ref.RefID) {
+				a.CVEs = append(a.CVEs, ref.RefID)
+			}
+		}
+		for _, cve := range def.AdvisoryCVEs {
+			if cve = strings.TrimSpace(cve); cve != "" && !containsString(a.CVEs, cve) {
+				a.CVEs = append(a.CVEs, cve)
+			}
+		}
+		if len(a.CVEs) > 0 {
+			a.ID = a.CVEs[0]
+		}
+
+		var walk func(c ovalCriteria)
+		walk = func(c ovalCriteria) {
+			for _, crit := range c.Criterions {
+				test, ok := tests[crit.TestRef]
+				if !ok {
+					continue
+				}
+				names, ok := objects[test.Object.Ref]
+				if !ok {
+					continue
+				}
+				events := []versionEvent{{Introduced: "0"}}
+				if test.State.Ref != "" {
+					fixed, ok := states[test.State.Ref]
+					if !ok {
+						continue
+					}
+					events = append(events, versionEvent{Fixed: fixed})
+				}
+				for _, name := range names {
+					a.Affected = append(a.Affected, affectedPackage{Name: name, Events: events})
+				}
+			}
+			for _, sub := range c.Criteria {
+				walk(sub)
+			}
+		}
+		walk(def.Criteria)
+
+		if len(a.Affected) > 0 {
+			advisories = append(advisories, a)
+		}
+	}
+	return advisories
+}
+
+// matchAdvisories reports every installed package affected by an advisory
+// for this distribution release.
+func matchAdvisories(advisories []advisory, packages []inventory.Package, osID, versionID string) []VulnerabilityFinding {
+	byName := map[string][]inventory.Package{}
+	for _, p := range packages {
+		if p.Source != "dpkg" && p.Source != "rpm" {
+			continue
+		}
+		byName[p.Name] = append(byName[p.Name], p)
+		if p.SourceName != "" {
+			byName[p.SourceName] = append(byName[p.SourceName], p)
+		}
+	}
+
+	var findings []VulnerabilityFinding
+	seen := map[string]bool{}
+	for _, a := range advisories {
+		for _, aff := range a.Affected {
+			if !ecosystemMatches(aff.Ecosystem, osID, versionID) {
+				continue
+			}
+			for _, p := range byName[aff.Name] {
+				compare := compareDpkgVersions
+				if p.Source == "rpm" {
+					compare = compareRPMVersions
+				}
+				affected, fixed := versionAffected(p.Version, aff, compare)
+				key := a.ID + "|" + p.Name + "|" + p.Architecture
+				if !affected || seen[key] {
+					continue
+				}
+				seen[key] = true
+				findings = append(findings, VulnerabilityFinding{
+					Package:          p.Name,
+					InstalledVersion: p.Version,
+					FixedVersion:     fixed,
+					Advisory:         a.ID,
+					CVEs:             a.CVEs,
+					Severity:         a.Severity,
+				})
+			}
+		}
+	}
+
+	sort.Slice(findings, func(i, j int) bool {
+		if findings[i].Package != findings[j].Package {
+			return findings[i].Package < findings[j].Package
+		}
+		return findings[i].Advisory < findings[j].Advisory
+	})
+	return findings
+}
+
+// versionAffected evaluates OSV range events in order: a version is
+// affected from an "introduced" event until a "fixed" (exclusive) or
+// "last_affected" (inclusive) event. It also returns the fix version.
+func versionAffected(version string, aff affectedPackage, compare func(a, b string) int) (bool, string) {
+	for _, v := range aff.Versions {
+		if compare(version, v) == 0 {
+			return true, ""
+		}
+	}
+
+	affected := false
+	fixed := ""
+	for _, ev := range aff.Events {
+		switch {
+		case ev.Introduced != "":
+			if ev.Introduced == "0" || compare(version, ev.Introduced) >= 0 {
+				affected = true
+			}
+		case ev.Fixed != "":
+			if compare(version, ev.Fixed) >= 0 {
+				affected = false
+			} else if affected && fixed == "" {
+				fixed = ev.Fixed
+			}
+		case ev.LastAffected != "":
+			if compare(version, ev.LastAffected) > 0 {
+				affected = false
+			}
+		}
+	}
+	if !affected {
+		return false, ""
+	}
+	return true, fixed
+}
+
+// ecosystemMatches checks an OSV ecosystem such as "Ubuntu:22.04:LTS" or
+// "Red Hat:enterprise_linux:9::appstream" against the host release.
+func ecosystemMatches(ecosystem, osID, versionID string) bool {
+	if ecosystem == "" {
+		return true
+	}
+	name, rest, _ := strings.Cut(ecosystem, ":")
+	if !containsString(osvEcosystemIDs[name], osID) {
+		return false
+	}
+
+	// The release is the first part that starts with a digit
+	for _, part := range strings.FieldsFunc(rest, func(r rune) bool { return r == ':' || r == ' ' }) {
+		if isDigit(part[0]) {
+			return part == versionID || strings.HasPrefix(versionID, part+".")
+		}
+	}
+	return true
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,25 @@
+//go:build linux
+// +build linux
+
+package checks
+
+import "sysutility/internal/inventory"
+
+// checkVulnerabilities matches the installed dpkg and rpm packages against
+// the advisory files on disk. Nothing is downloaded; without advisory
+// files the check is skipped.
+func checkVulnerabilities(policy Policy, packages []inventory.Package) *VulnerabilityStatus {
+	dir := policy.AdvisoryDir
+	if dir == "" {
+		dir = defaultAdvisoryDir
+	}
+	advisories := loadAdvisories(dir)
+	if len(advisories) == 0 {
+		return nil
+	}
+
+	release := readOSRelease()
+	status := &VulnerabilityStatus{AdvisoryDir: dir, AdvisoriesLoaded: len(advisories)}
+	summarizeFindings(status, matchAdvisories(advisories, packages, release["ID"], release["VERSION_ID"]))
+	return status
+}
@@ -0,0 +1,11 @@
+//go:build windows
+// +build windows
+
+package checks
+
+import "sysutility/internal/inventory"
+
+func checkVulnerabilities(policy Policy, packages []inventory.Package) *VulnerabilityStatus {
+	// Advisory matching needs dpkg or rpm package data
+	return nil
+}
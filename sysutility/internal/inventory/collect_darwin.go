@@ -0,0 +1,9 @@
+//go:build darwin
+// +build darwin
+
+package inventory
+
+func collectPackages() []Package {
+	// Not implemented yet
+	return nil
+}
@@ -0,0 +1,295 @@
+//go:build linux
+// +build linux
+
+package inventory
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func collectPackages() []Package {
+	var packages []Package
+	packages = append(packages, collectDpkg("/var/lib/dpkg")...)
+	packages = append(packages, collectRPM()...)
+	packages = append(packages, collectPacman("/var/lib/pacman/local")...)
+	packages = append(packages, collectFlatpak("/var/lib/flatpak")...)
+	packages = append(packages, collectSnap("/snap", "/var/lib/snapd/snaps")...)
+	return packages
+}
+
+// collectDpkg reads the dpkg status file. dpkg does not record install
+// times, so the mtime of the package's file list stands in for it.
+func collectDpkg(dir string) []Package {
+	data, err := os.ReadFile(filepath.Join(dir, "status"))
+	if err != nil {
+		return nil
+	}
+
+	var packages []Package
+	for _, stanza := range bytes.Split(data, []byte("\n\n")) {
+		fields := parseControlStanza(stanza)
+		if !strings.HasSuffix(fields["Status"], " installed") {
+			continue
+		}
+		p := Package{
+			Name:         fields["Package"],
+			Version:      fields["Version"],
+			Architecture: fields["Architecture"],
+			Source:       "dpkg",
+		}
+		// "Source: openssl (3.0.11-1)" when the source version differs
+		if source, _, _ := strings.Cut(fields["Source"], " "); source != "" && source != p.Name {
+			p.SourceName = source
+		}
+		for _, list := range []string{p.Name + ":" + p.Architecture + ".list", p.Name + ".list"} {
+			if info, err := os.Stat(filepath.Join(dir, "info", list)); err == nil {
+				p.InstalledAt = formatTime(info.ModTime())
+				break
+			}
+		}
+		packages = append(packages, p)
+	}
+	return packages
+}
+
+func parseControlStanza(stanza []byte) map[string]string {
+	fields := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(stanza))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || line[0] == ' ' || line[0] == '\t' {
+			continue
+		}
+		if key, value, ok := strings.Cut(line, ":"); ok {
+			fields[key] = strings.TrimSpace(value)
+		}
+	}
+	return fields
+}
+
+// collectRPM queries the RPM database through rpm itself, which reads
+// both the sqlite and the ndb backends.
+func collectRPM() []Package {
+	if _, err := exec.LookPath("rpm"); err != nil {
+		return nil
+	}
+	out, err := exec.Command("rpm", "-qa", "--qf", "%{NAME}\t%{EPOCHNUM}:%{VERSION}-%{RELEASE}\t%{ARCH}\t%{INSTALLTIME}\t%{SOURCERPM}\n").Output()
+	if err != nil {
+		return nil
+	}
+
+	var packages []Package
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		fields := strings.Split(scanner.Text(), "\t")
+		// gpg-pubkey pseudo packages are keys, not software
+		if len(fields) != 5 || fields[0] == "gpg-pubkey" {
+			continue
+		}
+		p := Package{
+			Name:         fields[0],
+			Version:      strings.TrimPrefix(fields[1], "0:"),
+			Architecture: fields[2],
+			Source:       "rpm",
+		}
+		if secs, err := strconv.ParseInt(fields[3], 10, 64); err == nil {
+			p.InstalledAt = formatTime(time.Unix(secs, 0))
+		}
+		if source := rpmSourceName(fields[4]); source != p.Name {
+			p.SourceName = source
+		}
+		packages = append(packages, p)
+	}
+	return packages
+}
+
+// rpmSourceName strips version, release and suffix from a source RPM file
+// name such as "openssl-3.0.7-18.el9.src.rpm".
+func rpmSourceName(srpm string) string {
+	name := strings.TrimSuffix(srpm, ".src.rpm")
+	for i := 0; i < 2; i++ {
+		dash := strings.LastIndex(name, "-")
+		if dash <= 0 {
+			return ""
+		}
+		name = name[:dash]
+	}
+	return name
+}
+
+// collectPacman reads the desc file of every package in pacman's local db.
+func collectPacman(dir string) []Package {
+	descs, _ := filepath.Glob(filepath.Join(dir, "*", "desc"))
+
+	var packages []Package
+	for _, desc := range descs {
+		data, err := os.ReadFile(desc)
+		if err != nil {
+			continue
+		}
+		fields := parsePacmanDesc(data)
+		p := Package{
+			Name:         fields["NAME"],
+			Version:      fields["VERSION"],
+			Architecture: fields["ARCH"],
+			Source:       "pacman",
+		}
+		if secs, err := strconv.ParseInt(fields["INSTALLDATE"], 10, 64); err == nil {
+			p.InstalledAt = formatTime(time.Unix(secs, 0))
+		}
+		if p.Name != "" {
+			packages = append(packages, p)
+		}
+	}
+	return packages
+}
+
+// parsePacmanDesc parses "%KEY%\nvalue\n\n" blocks, keeping the first
+// value line of each key.
+func parsePacmanDesc(data []byte) map[string]string {
+	fields := map[string]string{}
+	key := ""
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(line, "%") && strings.HasSuffix(line, "%") && len(line) > 1:
+			key = strings.Trim(line, "%")
+		case line == "":
+			key = ""
+		case key != "":
+			if _, ok := fields[key]; !ok {
+				fields[key] = line
+			}
+		}
+	}
+	return fields
+}
+
+// collectFlatpak walks the system installation. Each app/<id>/<arch>/<branch>
+// has an "active" link to the deployed commit, whose metadata has no
+// version, so the branch and deploy time are reported.
+func collectFlatpak(dir string) []Package {
+	var packages []Package
+	for _, kind := range []string{"app", "runtime"} {
+		actives, _ := filepath.Glob(filepath.Join(dir, kind, "*", "*", "*", "active"))
+		for _, active := range actives {
+			branchDir := filepath.Dir(active)
+			archDir := filepath.Dir(branchDir)
+			p := Package{
+				Name:         filepath.Base(filepath.Dir(archDir)),
+				Version:      filepath.Base(branchDir),
+				Architecture: filepath.Base(archDir),
+				Source:       "flatpak",
+			}
+			if version := flatpakAppdataVersion(active, p.Name); version != "" {
+				p.Version = version
+			}
+			if info, err := os.Stat(active); err == nil {
+				p.InstalledAt = formatTime(info.ModTime())
+			}
+			packages = append(packages, p)
+		}
+	}
+	return packages
+}
+
+// flatpakAppdataVersion reads the newest release from the app's metainfo.
+func flatpakAppdataVersion(deploy, id string) string {
+	for _, name := range []string{"metainfo", "appdata"} {
+		data, err := os.ReadFile(filepath.Join(deploy, "files", "share", name, id+"."+name+".xml"))
+		if err != nil {
+			continue
+		}
+		// Releases are listed newest first
+		_, rest, ok := strings.Cut(string(data), "<release ")
+		if !ok {
+			continue
+		}
+		_, rest, ok = strings.Cut(rest, `version="`)
+		if !ok {
+			continue
+		}
+		version, _, _ := strings.Cut(rest, `"`)
+		return version
+	}
+	return ""
+}
+
+// collectSnap reads meta/snap.yaml of the current revision of each snap.
+func collectSnap(mountDir, blobDir string) []Package {
+	currents, _ := filepath.Glob(filepath.Join(mountDir, "*", "current"))
+
+	var packages []Package
+	for _, current := range currents {
+		data, err := os.ReadFile(filepath.Join(current, "meta", "snap.yaml"))
+		if err != nil {
+			continue
+		}
+		p := parseSnapYAML(data)
+		p.Source = "snap"
+		if p.Name == "" {
+			p.Name = filepath.Base(filepath.Dir(current))
+		}
+		if revision, err := os.Readlink(current); err == nil {
+			blob := filepath.Join(blobDir, p.Name+"_"+filepath.Base(revision)+".snap")
+			if info, err := os.Stat(blob); err == nil {
+				p.InstalledAt = formatTime(info.ModTime())
+			}
+		}
+		packages = append(packages, p)
+	}
+	return packages
+}
+
+// parseSnapYAML pulls name, version and the first architecture out of
+// snap.yaml without a full YAML parser.
+func parseSnapYAML(data []byte) Package {
+	var p Package
+	inArchitectures := false
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		if inArchitectures {
+			if strings.HasPrefix(trimmed, "- ") {
+				if p.Architecture == "" {
+					p.Architecture = strings.TrimSpace(strings.TrimPrefix(trimmed, "- "))
+				}
+				continue
+			}
+			inArchitectures = false
+		}
+		if strings.HasPrefix(line, " ") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.Trim(strings.TrimSpace(value), `"'`)
+		switch key {
+		case "name":
+			p.Name = value
+		case "version":
+			p.Version = value
+		case "architectures":
+			inArchitectures = value == ""
+			if value != "" {
+				p.Architecture = strings.Trim(value, "[] ")
+			}
+		}
+	}
+	return p
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
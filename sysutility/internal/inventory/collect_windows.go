@@ -0,0 +1,9 @@
+//go:build windows
+// +build windows
+
+package inventory
+
+func collectPackages() []Package {
+	// Not implemented yet
+	return nil
+}
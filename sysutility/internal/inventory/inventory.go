@@ -0,0 +1,87 @@
+package inventory
+
+import (
+	"sort"
+)
+
+// Package is one piece of installed software.
+type Package struct {
+	Name         string `json:"name"`
+	Version      string `json:"version"`
+	Architecture string `json:"architecture,omitempty"`
+	// Source is the packaging system it came from: dpkg, rpm, pacman,
+	// flatpak or snap.
+	Source      string `json:"source"`
+	InstalledAt string `json:"installed_at,omitempty"`
+	// SourceName is the source package it was built from, which is what
+	// distribution advisories usually name.
+	SourceName string `json:"source_name,omitempty"`
+}
+
+// Key identifies a package across inventories. RPM can install several
+// versions of one package side by side (kernel, kernel-core, gpg-pubkey),
+// so RPMs are keyed on the version too; other sources on name and
+// architecture alone.
+func (p Package) Key() string {
+	key := p.Source + "/" + p.Name + ":" + p.Architecture
+	if p.Source == "rpm" {
+		key += "@" + p.Version
+	}
+	return key
+}
+
+// Inventory is the full software list of a machine.
+type Inventory struct {
+	MachineID string    `json:"machine_id"`
+	Packages  []Package `json:"packages"`
+}
+
+// Delta is what changed between two inventories. A Full delta replaces
+// the server's copy instead of being applied on top of it.
+type Delta struct {
+	MachineID string    `json:"machine_id"`
+	Full      bool      `json:"full"`
+	Upserted  []Package `json:"upserted,omitempty"`
+	Removed   []string  `json:"removed,omitempty"`
+}
+
+func (d Delta) Empty() bool {
+	return !d.Full && len(d.Upserted) == 0 && len(d.Removed) == 0
+}
+
+// Collect gathers the installed packages from every supported source.
+func Collect(machineID string) Inventory {
+	packages := collectPackages()
+	sort.Slice(packages, func(i, j int) bool {
+		if packages[i].Key() != packages[j].Key() {
+			return packages[i].Key() < packages[j].Key()
+		}
+		return packages[i].Version < packages[j].Version
+	})
+	return Inventory{MachineID: machineID, Packages: packages}
+}
+
+// Diff computes the delta that turns old into new. A nil old produces a
+// full upload.
+func Diff(old *Inventory, new Inventory) Delta {
+	if old == nil {
+		return Delta{MachineID: new.MachineID, Full: true, Upserted: new.Packages}
+	}
+
+	delta := Delta{MachineID: new.MachineID}
+	previous := map[string]Package{}
+	for _, p := range old.Packages {
+		previous[p.Key()] = p
+	}
+	for _, p := range new.Packages {
+		if prev, ok := previous[p.Key()]; !ok || prev != p {
+			delta.Upserted = append(delta.Upserted, p)
+		}
+		delete(previous, p.Key())
+	}
+	for key := range previous {
+		delta.Removed = append(delta.Removed, key)
+	}
+	sort.Strings(delta.Removed)
+	return delta
+}
@@ -0,0 +1,56 @@
+package inventory
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffMultipleInstalledVersions(t *testing.T) {
+	older := Package{Name: "kernel-core", Version: "6.8.5-301.fc40", Architecture: "x86_64", Source: "rpm"}
+	newer := Package{Name: "kernel-core", Version: "6.9.7-200.fc40", Architecture: "x86_64", Source: "rpm"}
+	newest := Package{Name: "kernel-core", Version: "6.10.3-200.fc40", Architecture: "x86_64", Source: "rpm"}
+
+	tests := []struct {
+		name string
+		old  []Package
+		new  []Package
+		want Delta
+	}{
+		{
+			name: "unchanged",
+			old:  []Package{older, newer},
+			new:  []Package{newer, older},
+			want: Delta{MachineID: "m"},
+		},
+		{
+			name: "kernel installed and oldest removed",
+			old:  []Package{older, newer},
+			new:  []Package{newer, newest},
+			want: Delta{
+				MachineID: "m",
+				Upserted:  []Package{newest},
+				Removed:   []string{older.Key()},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Diff(&Inventory{MachineID: "m", Packages: tt.old}, Inventory{MachineID: "m", Packages: tt.new})
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffDpkgUpgrade(t *testing.T) {
+	old := Package{Name: "openssl", Version: "3.0.2-0ubuntu1.15", Architecture: "amd64", Source: "dpkg"}
+	upgraded := old
+	upgraded.Version = "3.0.2-0ubuntu1.16"
+
+	got := Diff(&Inventory{Packages: []Package{old}}, Inventory{Packages: []Package{upgraded}})
+	want := Delta{Upserted: []Package{upgraded}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
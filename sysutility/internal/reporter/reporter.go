@@ -3,40 +3,134 @@ package reporter
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"sysutility/internal/checks"
+	"sysutility/internal/inventory"
+	"time"
 )
 
-var reportURL = "http://localhost:5000/api/systems/report"
+var (
+	reportURL    = "http://localhost:5000/api/systems/report"
+	inventoryURL = "http://localhost:5000/api/systems/inventory"
+)
+
+// serverClockSkew is local time minus the server's Date header, measured
+// on the last response from the server.
+var (
+	serverClockSkew      time.Duration
+	serverClockSkewKnown bool
+)
+
+// ServerClockSkew returns the skew measured on the last server response.
+func ServerClockSkew() (time.Duration, bool) {
+	return serverClockSkew, serverClockSkewKnown
+}
 
-func SendWithAuth(report checks.SystemReport, token string) {
+// ErrInventoryResync means the server has no base inventory to apply a
+// delta to and wants a full upload.
+var ErrInventoryResync = errors.New("server requested full inventory")
 
+// ErrInventoryTooLarge means the server refused the upload's size. Sending
+// the same upload again cannot succeed until the server limit changes.
+var ErrInventoryTooLarge = errors.New("server rejected inventory as too large")
+
+// SendWithAuth uploads a report. The caller should only treat the report
+// as delivered, and stop resending it, when no error is returned.
+func SendWithAuth(report checks.SystemReport, token string) error {
 	body, err := json.Marshal(report)
 	if err != nil {
-		fmt.Println("Error marshaling report:", err)
-		return
+		return fmt.Errorf("error marshaling report: %v", err)
 	}
 
 	req, err := http.NewRequest("POST", reportURL, bytes.NewBuffer(body))
 	if err != nil {
-		fmt.Println("Error creating request:", err)
-		return
+		return fmt.Errorf("error creating request: %v", err)
 	}
 	req.Header.Set("Authorization", "Bearer "+token)
 	req.Header.Set("Content-Type", "application/json")
 
 	client := &http.Client{}
+	sent := time.Now()
 	resp, err := client.Do(req)
 	if err != nil {
-		fmt.Println("Error sending request:", err)
-		return
+		return fmt.Errorf("error sending request: %v", err)
 	}
 	defer resp.Body.Close()
 
 	fmt.Println("Response Status:", resp.Status)
+	recordClockSkew(resp, sent, time.Now())
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("server returned non-success status: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// SendInventory uploads an inventory delta. Unlike reports, the caller
+// needs to know whether it was accepted, since the next delta is computed
+// against the last acknowledged inventory.
+func SendInventory(delta inventory.Delta, token string) error {
+	body, err := json.Marshal(delta)
+	if err != nil {
+		return fmt.Errorf("error marshaling inventory: %v", err)
+	}
+
+	req, err := http.NewRequest("POST", inventoryURL, bytes.NewBuffer(body))
+	if err != nil {
+		return fmt.Errorf("error creating request: %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{}
+	sent := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error sending request: %v", err)
+	}
+	defer resp.Body.Close()
+	recordClockSkew(resp, sent, time.Now())
 
+	if resp.StatusCode == http.StatusConflict {
+		return ErrInventoryResync
+	}
+	if resp.StatusCode == http.StatusRequestEntityTooLarge {
+		return ErrInventoryTooLarge
+	}
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		fmt.Println("Server returned non-success status:", resp.StatusCode)
+		return fmt.Errorf("server returned non-success status: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// MeasureClockSkew sends a HEAD request to the report endpoint so the skew
+// stays current on intervals where nothing is uploaded. Only the Date
+// header of the response matters, whatever its status.
+func MeasureClockSkew() error {
+	client := &http.Client{Timeout: 10 * time.Second}
+	sent := time.Now()
+	resp, err := client.Head(reportURL)
+	if err != nil {
+		// An old measurement says nothing about the clock now
+		serverClockSkewKnown = false
+		return fmt.Errorf("error sending request: %v", err)
+	}
+	resp.Body.Close()
+	recordClockSkew(resp, sent, time.Now())
+	return nil
+}
+
+// recordClockSkew compares the local clock with the response's Date
+// header, taking the middle of the round trip as the moment the server
+// stamped it.
+func recordClockSkew(resp *http.Response, sent, received time.Time) {
+	serverTime, err := http.ParseTime(resp.Header.Get("Date"))
+	if err != nil {
+		return
 	}
+	local := sent.Add(received.Sub(sent) / 2)
+	serverClockSkew = local.Sub(serverTime)
+	serverClockSkewKnown = true
 }